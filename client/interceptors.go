@@ -2,26 +2,54 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
 const (
-	authTokenKey   string = "auth_token"
-	authTokenValue string = "authd"
+	authorizationKey string = "authorization"
+	authTokenEnv     string = "TODO_AUTH_TOKEN"
 )
 
-func unaryAuthInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-	ctx = metadata.AppendToOutgoingContext(ctx, authTokenKey, authTokenValue)
-	return invoker(ctx, method, req, reply, cc, opts...)
+// authToken returns the token sent to the server: token if set,
+// otherwise the content of tokenFile if set, otherwise the value of the
+// TODO_AUTH_TOKEN environment variable, which can be empty.
+func authToken(token, tokenFile string) (string, error) {
+	if token != "" {
+		return token, nil
+	}
+	if tokenFile != "" {
+		b, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", err
+		}
+		token = strings.TrimSpace(string(b))
+		if token == "" {
+			return "", fmt.Errorf("%s: empty token", tokenFile)
+		}
+		return token, nil
+	}
+	return os.Getenv(authTokenEnv), nil
+}
+
+func unaryAuthInterceptor(token string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, authorizationKey, "Bearer "+token)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
 }
 
-func streamAuthInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
-	ctx = metadata.AppendToOutgoingContext(ctx, authTokenKey, authTokenValue)
-	s, err := streamer(ctx, desc, cc, method, opts...)
-	if err != nil {
-		return nil, err
+func streamAuthInterceptor(token string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = metadata.AppendToOutgoingContext(ctx, authorizationKey, "Bearer "+token)
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
 	}
-	return s, nil
 }
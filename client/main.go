@@ -2,30 +2,48 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
-	"os"
 	"time"
 
 	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials"
 	_ "google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+var (
+	token     = flag.String("token", "", "auth token sent to the server")
+	tokenFile = flag.String("token-file", "", "file containing the auth token, if -token isn't set")
+
+	caFile     = flag.String("ca", "./certs/ca_cert.pem", "PEM CA certificates the server certificate should be signed by")
+	serverName = flag.String("server-name", "x.test.example.com", "name the server certificate should be valid for")
+	certFile   = flag.String("cert", "", "PEM client certificate, for the servers requiring mTLS")
+	keyFile    = flag.String("key", "", "PEM private key of -cert")
+)
+
 func main() {
-	args := os.Args[1:]
+	flag.Parse()
+	args := flag.Args()
 	if len(args) == 0 {
-		log.Fatalln("usage: client [IP_ADDR]")
+		log.Fatalln("usage: client [flags] [IP_ADDR]")
 	}
 	addr := args[0]
 
-	creds, err := credentials.NewClientTLSFromFile("./certs/ca_cert.pem", "x.test.example.com")
+	authTok, err := authToken(*token, *tokenFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if authTok == "" && *certFile == "" {
+		log.Fatalf("no credential, use -token, -token-file, %s or -cert\n", authTokenEnv)
+	}
+
+	creds, err := newClientCredentials(*caFile, *serverName, *certFile, *keyFile)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -33,11 +51,16 @@ func main() {
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(creds),
 		// grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(unaryAuthInterceptor),
-		grpc.WithStreamInterceptor(streamAuthInterceptor),
 		// grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)),
 		grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"round_robin": {}}]}`),
 	}
+	// with mTLS, the certificate can be the only credential
+	if authTok != "" {
+		opts = append(opts,
+			grpc.WithUnaryInterceptor(unaryAuthInterceptor(authTok)),
+			grpc.WithStreamInterceptor(streamAuthInterceptor(authTok)),
+		)
+	}
 	conn, err := grpc.Dial(addr, opts...)
 	if err != nil {
 		log.Fatalf("did not connect: %v", err)
@@ -68,19 +91,38 @@ func main() {
 
 	fmt.Println("-----Update----")
 	updateTasks(c, []*pb.UpdateTasksRequest{
-		{Id: id1, Description: "A better name for task 1"},
-		{Id: id2, DueDate: timestamppb.New(dueDate.Add(5 * time.Hour))},
-		{Id: id3, Done: true},
+		{
+			Id:          id1,
+			Description: "A better name for task 1",
+			UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"description"}},
+		},
+		{
+			Id:         id2,
+			DueDate:    timestamppb.New(dueDate.Add(5 * time.Hour)),
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"due_date"}},
+		},
+		{
+			Id:         id3,
+			Done:       true,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"done"}},
+		},
 	}...)
 	// printTasks(c, fm)
 	fmt.Println("-------------")
 
 	fmt.Println("-----Delete----")
-	deleteTasks(c, []*pb.DeleteTasksRequest{
+	results := deleteTasks(c, []*pb.DeleteTasksRequest{
 		{Id: id1},
 		{Id: id2},
 		{Id: id3},
 	}...)
+	for _, res := range results {
+		if res.Status == pb.DeleteTasksResponse_STATUS_ERROR {
+			fmt.Printf("task %d: %s: %s\n", res.Id, res.Status, res.Error)
+			continue
+		}
+		fmt.Printf("task %d: %s\n", res.Id, res.Status)
+	}
 	fmt.Println("-------------")
 
 	fmt.Println("-----Error----")
@@ -139,8 +181,8 @@ func printTasks(c pb.TodoServiceClient, fm *fieldmaskpb.FieldMask) {
 
 func updateTasks(c pb.TodoServiceClient, reqs ...*pb.UpdateTasksRequest) {
 	ctx := context.Background()
-	// change "authd" to anything else to see the error
-	// ctx = metadata.AppendToOutgoingContext(ctx, "auth_token", "authd")
+	// a second token is rejected, uncomment to see the error
+	// ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer other")
 	stream, err := c.UpdateTasks(ctx)
 	if err != nil {
 		log.Fatalf("unexpected error: %v", err)
@@ -157,20 +199,28 @@ func updateTasks(c pb.TodoServiceClient, reqs ...*pb.UpdateTasksRequest) {
 			fmt.Printf("updated task with id: %d\n", req.Id)
 		}
 	}
-	if _, err := stream.CloseAndRecv(); err != nil {
+	res, err := stream.CloseAndRecv()
+	if err != nil {
 		log.Fatalf("unexpected error: %v", err)
 	}
+	fmt.Printf("applied %d updates, %d failed\n", res.Applied, res.Failed)
+	for _, f := range res.Failures {
+		fmt.Printf("failed updating task %d: %s\n", f.Id, f.Reason)
+	}
 }
 
-func deleteTasks(c pb.TodoServiceClient, reqs ...*pb.DeleteTasksRequest) {
+// deleteTasks deletes the tasks and returns the result for each
+// request, in order.
+func deleteTasks(c pb.TodoServiceClient, reqs ...*pb.DeleteTasksRequest) []*pb.DeleteTasksResponse {
 	stream, err := c.DeleteTasks(context.Background())
 	if err != nil {
 		log.Fatalf("unexpected error: %v", err)
 	}
 	waitc := make(chan struct{})
+	results := make([]*pb.DeleteTasksResponse, 0, len(reqs))
 	go func() {
 		for {
-			_, err := stream.Recv()
+			res, err := stream.Recv()
 			if err == io.EOF {
 				close(waitc)
 				break
@@ -178,16 +228,18 @@ func deleteTasks(c pb.TodoServiceClient, reqs ...*pb.DeleteTasksRequest) {
 			if err != nil {
 				log.Fatalf("error while receiving: %v", err)
 			}
-			log.Println("deleted tasks")
+			results = append(results, res)
 		}
 	}()
 	for _, req := range reqs {
+		// on failure, the error is returned by Recv
 		if err := stream.Send(req); err != nil {
-			return
+			break
 		}
 	}
 	if err := stream.CloseSend(); err != nil {
-		return
+		log.Fatalf("unexpected error: %v", err)
 	}
 	<-waitc
+	return results
 }
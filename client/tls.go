@@ -0,0 +1,43 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// newClientCredentials returns the TLS credentials of the client,
+// verifying that the server has a certificate for serverName signed by
+// one of the CAs of the PEM file caFile. If certFile and keyFile aren't
+// empty, the certificate and key in these PEM files are presented to
+// the servers requiring mTLS.
+func newClientCredentials(caFile, serverName, certFile, keyFile string) (credentials.TransportCredentials, error) {
+	b, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("%s: no PEM certificate", caFile)
+	}
+	cfg := &tls.Config{
+		RootCAs:    roots,
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("-cert and -key should be set together")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(cfg), nil
+}
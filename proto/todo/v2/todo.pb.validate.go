@@ -91,6 +91,10 @@ func (m *Task) validate(all bool) error {
 		}
 	}
 
+	// no validation rules for Version
+
+	// no validation rules for Owner
+
 	if len(errors) > 0 {
 		return TaskMultiError(errors)
 	}
@@ -411,71 +415,87 @@ var _ interface {
 	ErrorName() string
 } = AddTaskResponseValidationError{}
 
-// Validate checks the field values on ListTasksRequest with the rules defined
+// Validate checks the field values on AddTasksRequest with the rules defined
 // in the proto definition for this message. If any rules are violated, the
 // first error encountered is returned, or nil if there are no violations.
-func (m *ListTasksRequest) Validate() error {
+func (m *AddTasksRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on ListTasksRequest with the rules
+// ValidateAll checks the field values on AddTasksRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// ListTasksRequestMultiError, or nil if none found.
-func (m *ListTasksRequest) ValidateAll() error {
+// AddTasksRequestMultiError, or nil if none found.
+func (m *AddTasksRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *ListTasksRequest) validate(all bool) error {
+func (m *AddTasksRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	if all {
-		switch v := interface{}(m.GetMask()).(type) {
-		case interface{ ValidateAll() error }:
-			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, ListTasksRequestValidationError{
-					field:  "Mask",
-					reason: "embedded message failed validation",
-					cause:  err,
-				})
+	if l := len(m.GetTasks()); l < 1 || l > 1000 {
+		err := AddTasksRequestValidationError{
+			field:  "Tasks",
+			reason: "value must contain between 1 and 1000 items, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	for idx, item := range m.GetTasks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AddTasksRequestValidationError{
+						field:  fmt.Sprintf("Tasks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AddTasksRequestValidationError{
+						field:  fmt.Sprintf("Tasks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
 			}
-		case interface{ Validate() error }:
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
 			if err := v.Validate(); err != nil {
-				errors = append(errors, ListTasksRequestValidationError{
-					field:  "Mask",
+				return AddTasksRequestValidationError{
+					field:  fmt.Sprintf("Tasks[%v]", idx),
 					reason: "embedded message failed validation",
 					cause:  err,
-				})
-			}
-		}
-	} else if v, ok := interface{}(m.GetMask()).(interface{ Validate() error }); ok {
-		if err := v.Validate(); err != nil {
-			return ListTasksRequestValidationError{
-				field:  "Mask",
-				reason: "embedded message failed validation",
-				cause:  err,
+				}
 			}
 		}
+
 	}
 
 	if len(errors) > 0 {
-		return ListTasksRequestMultiError(errors)
+		return AddTasksRequestMultiError(errors)
 	}
 
 	return nil
 }
 
-// ListTasksRequestMultiError is an error wrapping multiple validation errors
-// returned by ListTasksRequest.ValidateAll() if the designated constraints
+// AddTasksRequestMultiError is an error wrapping multiple validation errors
+// returned by AddTasksRequest.ValidateAll() if the designated constraints
 // aren't met.
-type ListTasksRequestMultiError []error
+type AddTasksRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m ListTasksRequestMultiError) Error() string {
+func (m AddTasksRequestMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -484,11 +504,11 @@ func (m ListTasksRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m ListTasksRequestMultiError) AllErrors() []error { return m }
+func (m AddTasksRequestMultiError) AllErrors() []error { return m }
 
-// ListTasksRequestValidationError is the validation error returned by
-// ListTasksRequest.Validate if the designated constraints aren't met.
-type ListTasksRequestValidationError struct {
+// AddTasksRequestValidationError is the validation error returned by
+// AddTasksRequest.Validate if the designated constraints aren't met.
+type AddTasksRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -496,22 +516,22 @@ type ListTasksRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e ListTasksRequestValidationError) Field() string { return e.field }
+func (e AddTasksRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e ListTasksRequestValidationError) Reason() string { return e.reason }
+func (e AddTasksRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e ListTasksRequestValidationError) Cause() error { return e.cause }
+func (e AddTasksRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e ListTasksRequestValidationError) Key() bool { return e.key }
+func (e AddTasksRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e ListTasksRequestValidationError) ErrorName() string { return "ListTasksRequestValidationError" }
+func (e AddTasksRequestValidationError) ErrorName() string { return "AddTasksRequestValidationError" }
 
 // Error satisfies the builtin error interface
-func (e ListTasksRequestValidationError) Error() string {
+func (e AddTasksRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -523,14 +543,14 @@ func (e ListTasksRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sListTasksRequest.%s: %s%s",
+		"invalid %sAddTasksRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = ListTasksRequestValidationError{}
+var _ error = AddTasksRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -538,75 +558,44 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = ListTasksRequestValidationError{}
+} = AddTasksRequestValidationError{}
 
-// Validate checks the field values on ListTasksResponse with the rules defined
+// Validate checks the field values on AddTasksResponse with the rules defined
 // in the proto definition for this message. If any rules are violated, the
 // first error encountered is returned, or nil if there are no violations.
-func (m *ListTasksResponse) Validate() error {
+func (m *AddTasksResponse) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on ListTasksResponse with the rules
+// ValidateAll checks the field values on AddTasksResponse with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// ListTasksResponseMultiError, or nil if none found.
-func (m *ListTasksResponse) ValidateAll() error {
+// AddTasksResponseMultiError, or nil if none found.
+func (m *AddTasksResponse) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *ListTasksResponse) validate(all bool) error {
+func (m *AddTasksResponse) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	if all {
-		switch v := interface{}(m.GetTask()).(type) {
-		case interface{ ValidateAll() error }:
-			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, ListTasksResponseValidationError{
-					field:  "Task",
-					reason: "embedded message failed validation",
-					cause:  err,
-				})
-			}
-		case interface{ Validate() error }:
-			if err := v.Validate(); err != nil {
-				errors = append(errors, ListTasksResponseValidationError{
-					field:  "Task",
-					reason: "embedded message failed validation",
-					cause:  err,
-				})
-			}
-		}
-	} else if v, ok := interface{}(m.GetTask()).(interface{ Validate() error }); ok {
-		if err := v.Validate(); err != nil {
-			return ListTasksResponseValidationError{
-				field:  "Task",
-				reason: "embedded message failed validation",
-				cause:  err,
-			}
-		}
-	}
-
-	// no validation rules for Overdue
-
 	if len(errors) > 0 {
-		return ListTasksResponseMultiError(errors)
+		return AddTasksResponseMultiError(errors)
 	}
 
 	return nil
 }
 
-// ListTasksResponseMultiError is an error wrapping multiple validation errors
-// returned by ListTasksResponse.ValidateAll() if the designated constraints
+// AddTasksResponseMultiError is an error wrapping multiple validation errors
+// returned by AddTasksResponse.ValidateAll() if the designated constraints
 // aren't met.
-type ListTasksResponseMultiError []error
+type AddTasksResponseMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m ListTasksResponseMultiError) Error() string {
+func (m AddTasksResponseMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -615,11 +604,11 @@ func (m ListTasksResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m ListTasksResponseMultiError) AllErrors() []error { return m }
+func (m AddTasksResponseMultiError) AllErrors() []error { return m }
 
-// ListTasksResponseValidationError is the validation error returned by
-// ListTasksResponse.Validate if the designated constraints aren't met.
-type ListTasksResponseValidationError struct {
+// AddTasksResponseValidationError is the validation error returned by
+// AddTasksResponse.Validate if the designated constraints aren't met.
+type AddTasksResponseValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -627,24 +616,22 @@ type ListTasksResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e ListTasksResponseValidationError) Field() string { return e.field }
+func (e AddTasksResponseValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e ListTasksResponseValidationError) Reason() string { return e.reason }
+func (e AddTasksResponseValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e ListTasksResponseValidationError) Cause() error { return e.cause }
+func (e AddTasksResponseValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e ListTasksResponseValidationError) Key() bool { return e.key }
+func (e AddTasksResponseValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e ListTasksResponseValidationError) ErrorName() string {
-	return "ListTasksResponseValidationError"
-}
+func (e AddTasksResponseValidationError) ErrorName() string { return "AddTasksResponseValidationError" }
 
 // Error satisfies the builtin error interface
-func (e ListTasksResponseValidationError) Error() string {
+func (e AddTasksResponseValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -656,14 +643,14 @@ func (e ListTasksResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sListTasksResponse.%s: %s%s",
+		"invalid %sAddTasksResponse.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = ListTasksResponseValidationError{}
+var _ error = AddTasksResponseValidationError{}
 
 var _ interface {
 	Field() string
@@ -671,79 +658,109 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = ListTasksResponseValidationError{}
+} = AddTasksResponseValidationError{}
 
-// Validate checks the field values on UpdateTasksRequest with the rules
-// defined in the proto definition for this message. If any rules are
-// violated, the first error encountered is returned, or nil if there are no violations.
-func (m *UpdateTasksRequest) Validate() error {
+// Validate checks the field values on TaskFilter with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *TaskFilter) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on UpdateTasksRequest with the rules
-// defined in the proto definition for this message. If any rules are
-// violated, the result is a list of violation errors wrapped in
-// UpdateTasksRequestMultiError, or nil if none found.
-func (m *UpdateTasksRequest) ValidateAll() error {
+// ValidateAll checks the field values on TaskFilter with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TaskFilterMultiError, or
+// nil if none found.
+func (m *TaskFilter) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *UpdateTasksRequest) validate(all bool) error {
+func (m *TaskFilter) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Id
-
-	// no validation rules for Description
+	// no validation rules for OverdueOnly
 
-	// no validation rules for Done
+	if all {
+		switch v := interface{}(m.GetDueAfter()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, TaskFilterValidationError{
+					field:  "DueAfter",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, TaskFilterValidationError{
+					field:  "DueAfter",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetDueAfter()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return TaskFilterValidationError{
+				field:  "DueAfter",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
 
 	if all {
-		switch v := interface{}(m.GetDueDate()).(type) {
+		switch v := interface{}(m.GetDueBefore()).(type) {
 		case interface{ ValidateAll() error }:
 			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, UpdateTasksRequestValidationError{
-					field:  "DueDate",
+				errors = append(errors, TaskFilterValidationError{
+					field:  "DueBefore",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				errors = append(errors, UpdateTasksRequestValidationError{
-					field:  "DueDate",
+				errors = append(errors, TaskFilterValidationError{
+					field:  "DueBefore",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		}
-	} else if v, ok := interface{}(m.GetDueDate()).(interface{ Validate() error }); ok {
+	} else if v, ok := interface{}(m.GetDueBefore()).(interface{ Validate() error }); ok {
 		if err := v.Validate(); err != nil {
-			return UpdateTasksRequestValidationError{
-				field:  "DueDate",
+			return TaskFilterValidationError{
+				field:  "DueBefore",
 				reason: "embedded message failed validation",
 				cause:  err,
 			}
 		}
 	}
 
+	// no validation rules for DescriptionContains
+
+	if m.Done != nil {
+		// no validation rules for Done
+	}
+
 	if len(errors) > 0 {
-		return UpdateTasksRequestMultiError(errors)
+		return TaskFilterMultiError(errors)
 	}
 
 	return nil
 }
 
-// UpdateTasksRequestMultiError is an error wrapping multiple validation errors
-// returned by UpdateTasksRequest.ValidateAll() if the designated constraints
-// aren't met.
-type UpdateTasksRequestMultiError []error
+// TaskFilterMultiError is an error wrapping multiple validation errors
+// returned by TaskFilter.ValidateAll() if the designated constraints aren't met.
+type TaskFilterMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m UpdateTasksRequestMultiError) Error() string {
+func (m TaskFilterMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -752,11 +769,11 @@ func (m UpdateTasksRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m UpdateTasksRequestMultiError) AllErrors() []error { return m }
+func (m TaskFilterMultiError) AllErrors() []error { return m }
 
-// UpdateTasksRequestValidationError is the validation error returned by
-// UpdateTasksRequest.Validate if the designated constraints aren't met.
-type UpdateTasksRequestValidationError struct {
+// TaskFilterValidationError is the validation error returned by
+// TaskFilter.Validate if the designated constraints aren't met.
+type TaskFilterValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -764,24 +781,22 @@ type UpdateTasksRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e UpdateTasksRequestValidationError) Field() string { return e.field }
+func (e TaskFilterValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e UpdateTasksRequestValidationError) Reason() string { return e.reason }
+func (e TaskFilterValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e UpdateTasksRequestValidationError) Cause() error { return e.cause }
+func (e TaskFilterValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e UpdateTasksRequestValidationError) Key() bool { return e.key }
+func (e TaskFilterValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e UpdateTasksRequestValidationError) ErrorName() string {
-	return "UpdateTasksRequestValidationError"
-}
+func (e TaskFilterValidationError) ErrorName() string { return "TaskFilterValidationError" }
 
 // Error satisfies the builtin error interface
-func (e UpdateTasksRequestValidationError) Error() string {
+func (e TaskFilterValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -793,14 +808,14 @@ func (e UpdateTasksRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sUpdateTasksRequest.%s: %s%s",
+		"invalid %sTaskFilter.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = UpdateTasksRequestValidationError{}
+var _ error = TaskFilterValidationError{}
 
 var _ interface {
 	Field() string
@@ -808,44 +823,47 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = UpdateTasksRequestValidationError{}
+} = TaskFilterValidationError{}
 
-// Validate checks the field values on UpdateTasksResponse with the rules
-// defined in the proto definition for this message. If any rules are
-// violated, the first error encountered is returned, or nil if there are no violations.
-func (m *UpdateTasksResponse) Validate() error {
+// Validate checks the field values on TaskOrder with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *TaskOrder) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on UpdateTasksResponse with the rules
-// defined in the proto definition for this message. If any rules are
-// violated, the result is a list of violation errors wrapped in
-// UpdateTasksResponseMultiError, or nil if none found.
-func (m *UpdateTasksResponse) ValidateAll() error {
+// ValidateAll checks the field values on TaskOrder with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TaskOrderMultiError, or nil
+// if none found.
+func (m *TaskOrder) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *UpdateTasksResponse) validate(all bool) error {
+func (m *TaskOrder) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
+	// no validation rules for Field
+
+	// no validation rules for Descending
+
 	if len(errors) > 0 {
-		return UpdateTasksResponseMultiError(errors)
+		return TaskOrderMultiError(errors)
 	}
 
 	return nil
 }
 
-// UpdateTasksResponseMultiError is an error wrapping multiple validation
-// errors returned by UpdateTasksResponse.ValidateAll() if the designated
-// constraints aren't met.
-type UpdateTasksResponseMultiError []error
+// TaskOrderMultiError is an error wrapping multiple validation errors returned
+// by TaskOrder.ValidateAll() if the designated constraints aren't met.
+type TaskOrderMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m UpdateTasksResponseMultiError) Error() string {
+func (m TaskOrderMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -854,11 +872,11 @@ func (m UpdateTasksResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m UpdateTasksResponseMultiError) AllErrors() []error { return m }
+func (m TaskOrderMultiError) AllErrors() []error { return m }
 
-// UpdateTasksResponseValidationError is the validation error returned by
-// UpdateTasksResponse.Validate if the designated constraints aren't met.
-type UpdateTasksResponseValidationError struct {
+// TaskOrderValidationError is the validation error returned by
+// TaskOrder.Validate if the designated constraints aren't met.
+type TaskOrderValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -866,24 +884,22 @@ type UpdateTasksResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e UpdateTasksResponseValidationError) Field() string { return e.field }
+func (e TaskOrderValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e UpdateTasksResponseValidationError) Reason() string { return e.reason }
+func (e TaskOrderValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e UpdateTasksResponseValidationError) Cause() error { return e.cause }
+func (e TaskOrderValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e UpdateTasksResponseValidationError) Key() bool { return e.key }
+func (e TaskOrderValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e UpdateTasksResponseValidationError) ErrorName() string {
-	return "UpdateTasksResponseValidationError"
-}
+func (e TaskOrderValidationError) ErrorName() string { return "TaskOrderValidationError" }
 
 // Error satisfies the builtin error interface
-func (e UpdateTasksResponseValidationError) Error() string {
+func (e TaskOrderValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -895,14 +911,14 @@ func (e UpdateTasksResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sUpdateTasksResponse.%s: %s%s",
+		"invalid %sTaskOrder.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = UpdateTasksResponseValidationError{}
+var _ error = TaskOrderValidationError{}
 
 var _ interface {
 	Field() string
@@ -910,59 +926,1065 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = UpdateTasksResponseValidationError{}
+} = TaskOrderValidationError{}
 
-// Validate checks the field values on DeleteTasksRequest with the rules
-// defined in the proto definition for this message. If any rules are
-// violated, the first error encountered is returned, or nil if there are no violations.
-func (m *DeleteTasksRequest) Validate() error {
+// Validate checks the field values on ListTasksRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ListTasksRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on DeleteTasksRequest with the rules
+// ValidateAll checks the field values on ListTasksRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// DeleteTasksRequestMultiError, or nil if none found.
-func (m *DeleteTasksRequest) ValidateAll() error {
+// ListTasksRequestMultiError, or nil if none found.
+func (m *ListTasksRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *DeleteTasksRequest) validate(all bool) error {
+func (m *ListTasksRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Id
-
-	if len(errors) > 0 {
-		return DeleteTasksRequestMultiError(errors)
-	}
-
-	return nil
-}
-
-// DeleteTasksRequestMultiError is an error wrapping multiple validation errors
-// returned by DeleteTasksRequest.ValidateAll() if the designated constraints
-// aren't met.
-type DeleteTasksRequestMultiError []error
-
-// Error returns a concatenation of all the error messages it wraps.
-func (m DeleteTasksRequestMultiError) Error() string {
-	var msgs []string
-	for _, err := range m {
-		msgs = append(msgs, err.Error())
-	}
-	return strings.Join(msgs, "; ")
-}
-
-// AllErrors returns a list of validation violation errors.
+	if all {
+		switch v := interface{}(m.GetMask()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ListTasksRequestValidationError{
+					field:  "Mask",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ListTasksRequestValidationError{
+					field:  "Mask",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetMask()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ListTasksRequestValidationError{
+				field:  "Mask",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetFilter()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ListTasksRequestValidationError{
+					field:  "Filter",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ListTasksRequestValidationError{
+					field:  "Filter",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetFilter()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ListTasksRequestValidationError{
+				field:  "Filter",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for PageSize
+
+	// no validation rules for PageToken
+
+	if all {
+		switch v := interface{}(m.GetOrderBy()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ListTasksRequestValidationError{
+					field:  "OrderBy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ListTasksRequestValidationError{
+					field:  "OrderBy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetOrderBy()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ListTasksRequestValidationError{
+				field:  "OrderBy",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return ListTasksRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListTasksRequestMultiError is an error wrapping multiple validation errors
+// returned by ListTasksRequest.ValidateAll() if the designated constraints
+// aren't met.
+type ListTasksRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListTasksRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListTasksRequestMultiError) AllErrors() []error { return m }
+
+// ListTasksRequestValidationError is the validation error returned by
+// ListTasksRequest.Validate if the designated constraints aren't met.
+type ListTasksRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListTasksRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListTasksRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListTasksRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListTasksRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListTasksRequestValidationError) ErrorName() string { return "ListTasksRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ListTasksRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListTasksRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListTasksRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListTasksRequestValidationError{}
+
+// Validate checks the field values on ListTasksResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ListTasksResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListTasksResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListTasksResponseMultiError, or nil if none found.
+func (m *ListTasksResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListTasksResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetTask()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ListTasksResponseValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ListTasksResponseValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTask()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ListTasksResponseValidationError{
+				field:  "Task",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Overdue
+
+	// no validation rules for NextPageToken
+
+	if len(errors) > 0 {
+		return ListTasksResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListTasksResponseMultiError is an error wrapping multiple validation errors
+// returned by ListTasksResponse.ValidateAll() if the designated constraints
+// aren't met.
+type ListTasksResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListTasksResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListTasksResponseMultiError) AllErrors() []error { return m }
+
+// ListTasksResponseValidationError is the validation error returned by
+// ListTasksResponse.Validate if the designated constraints aren't met.
+type ListTasksResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListTasksResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListTasksResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListTasksResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListTasksResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListTasksResponseValidationError) ErrorName() string {
+	return "ListTasksResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListTasksResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListTasksResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListTasksResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListTasksResponseValidationError{}
+
+// Validate checks the field values on GetTaskRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *GetTaskRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetTaskRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in GetTaskRequestMultiError,
+// or nil if none found.
+func (m *GetTaskRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetTaskRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if all {
+		switch v := interface{}(m.GetMask()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, GetTaskRequestValidationError{
+					field:  "Mask",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, GetTaskRequestValidationError{
+					field:  "Mask",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetMask()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return GetTaskRequestValidationError{
+				field:  "Mask",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return GetTaskRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetTaskRequestMultiError is an error wrapping multiple validation errors
+// returned by GetTaskRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetTaskRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetTaskRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetTaskRequestMultiError) AllErrors() []error { return m }
+
+// GetTaskRequestValidationError is the validation error returned by
+// GetTaskRequest.Validate if the designated constraints aren't met.
+type GetTaskRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetTaskRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetTaskRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetTaskRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetTaskRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetTaskRequestValidationError) ErrorName() string { return "GetTaskRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetTaskRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetTaskRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetTaskRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetTaskRequestValidationError{}
+
+// Validate checks the field values on GetTaskResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetTaskResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetTaskResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetTaskResponseMultiError, or nil if none found.
+func (m *GetTaskResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetTaskResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetTask()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, GetTaskResponseValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, GetTaskResponseValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTask()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return GetTaskResponseValidationError{
+				field:  "Task",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Overdue
+
+	if len(errors) > 0 {
+		return GetTaskResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetTaskResponseMultiError is an error wrapping multiple validation errors
+// returned by GetTaskResponse.ValidateAll() if the designated constraints
+// aren't met.
+type GetTaskResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetTaskResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetTaskResponseMultiError) AllErrors() []error { return m }
+
+// GetTaskResponseValidationError is the validation error returned by
+// GetTaskResponse.Validate if the designated constraints aren't met.
+type GetTaskResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetTaskResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetTaskResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetTaskResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetTaskResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetTaskResponseValidationError) ErrorName() string { return "GetTaskResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetTaskResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetTaskResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetTaskResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetTaskResponseValidationError{}
+
+// Validate checks the field values on UpdateTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpdateTasksRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpdateTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpdateTasksRequestMultiError, or nil if none found.
+func (m *UpdateTasksRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpdateTasksRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for Description
+
+	// no validation rules for Done
+
+	if all {
+		switch v := interface{}(m.GetDueDate()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UpdateTasksRequestValidationError{
+					field:  "DueDate",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UpdateTasksRequestValidationError{
+					field:  "DueDate",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetDueDate()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UpdateTasksRequestValidationError{
+				field:  "DueDate",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetUpdateMask()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UpdateTasksRequestValidationError{
+					field:  "UpdateMask",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UpdateTasksRequestValidationError{
+					field:  "UpdateMask",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUpdateMask()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UpdateTasksRequestValidationError{
+				field:  "UpdateMask",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for ExpectedVersion
+
+	if len(errors) > 0 {
+		return UpdateTasksRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// UpdateTasksRequestMultiError is an error wrapping multiple validation errors
+// returned by UpdateTasksRequest.ValidateAll() if the designated constraints
+// aren't met.
+type UpdateTasksRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpdateTasksRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpdateTasksRequestMultiError) AllErrors() []error { return m }
+
+// UpdateTasksRequestValidationError is the validation error returned by
+// UpdateTasksRequest.Validate if the designated constraints aren't met.
+type UpdateTasksRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpdateTasksRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpdateTasksRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpdateTasksRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpdateTasksRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpdateTasksRequestValidationError) ErrorName() string {
+	return "UpdateTasksRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpdateTasksRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpdateTasksRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpdateTasksRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpdateTasksRequestValidationError{}
+
+// Validate checks the field values on UpdateTasksResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpdateTasksResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpdateTasksResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpdateTasksResponseMultiError, or nil if none found.
+func (m *UpdateTasksResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpdateTasksResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Applied
+
+	// no validation rules for Failed
+
+	for idx, item := range m.GetFailures() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, UpdateTasksResponseValidationError{
+						field:  fmt.Sprintf("Failures[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, UpdateTasksResponseValidationError{
+						field:  fmt.Sprintf("Failures[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return UpdateTasksResponseValidationError{
+					field:  fmt.Sprintf("Failures[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return UpdateTasksResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// UpdateTasksResponseMultiError is an error wrapping multiple validation
+// errors returned by UpdateTasksResponse.ValidateAll() if the designated
+// constraints aren't met.
+type UpdateTasksResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpdateTasksResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpdateTasksResponseMultiError) AllErrors() []error { return m }
+
+// UpdateTasksResponseValidationError is the validation error returned by
+// UpdateTasksResponse.Validate if the designated constraints aren't met.
+type UpdateTasksResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpdateTasksResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpdateTasksResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpdateTasksResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpdateTasksResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpdateTasksResponseValidationError) ErrorName() string {
+	return "UpdateTasksResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpdateTasksResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpdateTasksResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpdateTasksResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpdateTasksResponseValidationError{}
+
+// Validate checks the field values on DeleteTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeleteTasksRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteTasksRequestMultiError, or nil if none found.
+func (m *DeleteTasksRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteTasksRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for ExpectedVersion
+
+	if len(errors) > 0 {
+		return DeleteTasksRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeleteTasksRequestMultiError is an error wrapping multiple validation errors
+// returned by DeleteTasksRequest.ValidateAll() if the designated constraints
+// aren't met.
+type DeleteTasksRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteTasksRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
 func (m DeleteTasksRequestMultiError) AllErrors() []error { return m }
 
-// DeleteTasksRequestValidationError is the validation error returned by
-// DeleteTasksRequest.Validate if the designated constraints aren't met.
-type DeleteTasksRequestValidationError struct {
+// DeleteTasksRequestValidationError is the validation error returned by
+// DeleteTasksRequest.Validate if the designated constraints aren't met.
+type DeleteTasksRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteTasksRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteTasksRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteTasksRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteTasksRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteTasksRequestValidationError) ErrorName() string {
+	return "DeleteTasksRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteTasksRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteTasksRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteTasksRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteTasksRequestValidationError{}
+
+// Validate checks the field values on DeleteTasksResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeleteTasksResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteTasksResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteTasksResponseMultiError, or nil if none found.
+func (m *DeleteTasksResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteTasksResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for Status
+
+	// no validation rules for Error
+
+	if len(errors) > 0 {
+		return DeleteTasksResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeleteTasksResponseMultiError is an error wrapping multiple validation
+// errors returned by DeleteTasksResponse.ValidateAll() if the designated
+// constraints aren't met.
+type DeleteTasksResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteTasksResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteTasksResponseMultiError) AllErrors() []error { return m }
+
+// DeleteTasksResponseValidationError is the validation error returned by
+// DeleteTasksResponse.Validate if the designated constraints aren't met.
+type DeleteTasksResponseValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -970,24 +1992,24 @@ type DeleteTasksRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e DeleteTasksRequestValidationError) Field() string { return e.field }
+func (e DeleteTasksResponseValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e DeleteTasksRequestValidationError) Reason() string { return e.reason }
+func (e DeleteTasksResponseValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e DeleteTasksRequestValidationError) Cause() error { return e.cause }
+func (e DeleteTasksResponseValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e DeleteTasksRequestValidationError) Key() bool { return e.key }
+func (e DeleteTasksResponseValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e DeleteTasksRequestValidationError) ErrorName() string {
-	return "DeleteTasksRequestValidationError"
+func (e DeleteTasksResponseValidationError) ErrorName() string {
+	return "DeleteTasksResponseValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e DeleteTasksRequestValidationError) Error() string {
+func (e DeleteTasksResponseValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -999,14 +2021,14 @@ func (e DeleteTasksRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sDeleteTasksRequest.%s: %s%s",
+		"invalid %sDeleteTasksResponse.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = DeleteTasksRequestValidationError{}
+var _ error = DeleteTasksResponseValidationError{}
 
 var _ interface {
 	Field() string
@@ -1014,44 +2036,181 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = DeleteTasksRequestValidationError{}
+} = DeleteTasksResponseValidationError{}
 
-// Validate checks the field values on DeleteTasksResponse with the rules
+// Validate checks the field values on WatchTasksRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *WatchTasksRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WatchTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// WatchTasksRequestMultiError, or nil if none found.
+func (m *WatchTasksRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WatchTasksRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for SinceRevision
+
+	if len(errors) > 0 {
+		return WatchTasksRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// WatchTasksRequestMultiError is an error wrapping multiple validation errors
+// returned by WatchTasksRequest.ValidateAll() if the designated constraints
+// aren't met.
+type WatchTasksRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WatchTasksRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WatchTasksRequestMultiError) AllErrors() []error { return m }
+
+// WatchTasksRequestValidationError is the validation error returned by
+// WatchTasksRequest.Validate if the designated constraints aren't met.
+type WatchTasksRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WatchTasksRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WatchTasksRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WatchTasksRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WatchTasksRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WatchTasksRequestValidationError) ErrorName() string {
+	return "WatchTasksRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e WatchTasksRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWatchTasksRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WatchTasksRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WatchTasksRequestValidationError{}
+
+// Validate checks the field values on WatchTasksResponse with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *DeleteTasksResponse) Validate() error {
+func (m *WatchTasksResponse) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on DeleteTasksResponse with the rules
+// ValidateAll checks the field values on WatchTasksResponse with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// DeleteTasksResponseMultiError, or nil if none found.
-func (m *DeleteTasksResponse) ValidateAll() error {
+// WatchTasksResponseMultiError, or nil if none found.
+func (m *WatchTasksResponse) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *DeleteTasksResponse) validate(all bool) error {
+func (m *WatchTasksResponse) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
+	// no validation rules for Type
+
+	if all {
+		switch v := interface{}(m.GetTask()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, WatchTasksResponseValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, WatchTasksResponseValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTask()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return WatchTasksResponseValidationError{
+				field:  "Task",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Revision
+
 	if len(errors) > 0 {
-		return DeleteTasksResponseMultiError(errors)
+		return WatchTasksResponseMultiError(errors)
 	}
 
 	return nil
 }
 
-// DeleteTasksResponseMultiError is an error wrapping multiple validation
-// errors returned by DeleteTasksResponse.ValidateAll() if the designated
-// constraints aren't met.
-type DeleteTasksResponseMultiError []error
+// WatchTasksResponseMultiError is an error wrapping multiple validation errors
+// returned by WatchTasksResponse.ValidateAll() if the designated constraints
+// aren't met.
+type WatchTasksResponseMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m DeleteTasksResponseMultiError) Error() string {
+func (m WatchTasksResponseMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1060,11 +2219,11 @@ func (m DeleteTasksResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m DeleteTasksResponseMultiError) AllErrors() []error { return m }
+func (m WatchTasksResponseMultiError) AllErrors() []error { return m }
 
-// DeleteTasksResponseValidationError is the validation error returned by
-// DeleteTasksResponse.Validate if the designated constraints aren't met.
-type DeleteTasksResponseValidationError struct {
+// WatchTasksResponseValidationError is the validation error returned by
+// WatchTasksResponse.Validate if the designated constraints aren't met.
+type WatchTasksResponseValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1072,24 +2231,24 @@ type DeleteTasksResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e DeleteTasksResponseValidationError) Field() string { return e.field }
+func (e WatchTasksResponseValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e DeleteTasksResponseValidationError) Reason() string { return e.reason }
+func (e WatchTasksResponseValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e DeleteTasksResponseValidationError) Cause() error { return e.cause }
+func (e WatchTasksResponseValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e DeleteTasksResponseValidationError) Key() bool { return e.key }
+func (e WatchTasksResponseValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e DeleteTasksResponseValidationError) ErrorName() string {
-	return "DeleteTasksResponseValidationError"
+func (e WatchTasksResponseValidationError) ErrorName() string {
+	return "WatchTasksResponseValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e DeleteTasksResponseValidationError) Error() string {
+func (e WatchTasksResponseValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1101,14 +2260,14 @@ func (e DeleteTasksResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sDeleteTasksResponse.%s: %s%s",
+		"invalid %sWatchTasksResponse.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = DeleteTasksResponseValidationError{}
+var _ error = WatchTasksResponseValidationError{}
 
 var _ interface {
 	Field() string
@@ -1116,4 +2275,113 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = DeleteTasksResponseValidationError{}
+} = WatchTasksResponseValidationError{}
+
+// Validate checks the field values on UpdateTasksResponse_Failure with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpdateTasksResponse_Failure) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpdateTasksResponse_Failure with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpdateTasksResponse_FailureMultiError, or nil if none found.
+func (m *UpdateTasksResponse_Failure) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpdateTasksResponse_Failure) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for Reason
+
+	// no validation rules for Code
+
+	if len(errors) > 0 {
+		return UpdateTasksResponse_FailureMultiError(errors)
+	}
+
+	return nil
+}
+
+// UpdateTasksResponse_FailureMultiError is an error wrapping multiple
+// validation errors returned by UpdateTasksResponse_Failure.ValidateAll() if
+// the designated constraints aren't met.
+type UpdateTasksResponse_FailureMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpdateTasksResponse_FailureMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpdateTasksResponse_FailureMultiError) AllErrors() []error { return m }
+
+// UpdateTasksResponse_FailureValidationError is the validation error returned
+// by UpdateTasksResponse_Failure.Validate if the designated constraints
+// aren't met.
+type UpdateTasksResponse_FailureValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpdateTasksResponse_FailureValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpdateTasksResponse_FailureValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpdateTasksResponse_FailureValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpdateTasksResponse_FailureValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpdateTasksResponse_FailureValidationError) ErrorName() string {
+	return "UpdateTasksResponse_FailureValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpdateTasksResponse_FailureValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpdateTasksResponse_Failure.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpdateTasksResponse_FailureValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpdateTasksResponse_FailureValidationError{}
@@ -20,9 +20,12 @@ const _ = grpc.SupportPackageIsVersion7
 
 const (
 	TodoService_AddTask_FullMethodName     = "/todo.v2.TodoService/AddTask"
+	TodoService_AddTasks_FullMethodName    = "/todo.v2.TodoService/AddTasks"
+	TodoService_GetTask_FullMethodName     = "/todo.v2.TodoService/GetTask"
 	TodoService_ListTasks_FullMethodName   = "/todo.v2.TodoService/ListTasks"
 	TodoService_UpdateTasks_FullMethodName = "/todo.v2.TodoService/UpdateTasks"
 	TodoService_DeleteTasks_FullMethodName = "/todo.v2.TodoService/DeleteTasks"
+	TodoService_WatchTasks_FullMethodName  = "/todo.v2.TodoService/WatchTasks"
 )
 
 // TodoServiceClient is the client API for TodoService service.
@@ -30,9 +33,12 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TodoServiceClient interface {
 	AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*AddTaskResponse, error)
+	AddTasks(ctx context.Context, in *AddTasksRequest, opts ...grpc.CallOption) (*AddTasksResponse, error)
+	GetTask(ctx context.Context, in *GetTaskRequest, opts ...grpc.CallOption) (*GetTaskResponse, error)
 	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (TodoService_ListTasksClient, error)
 	UpdateTasks(ctx context.Context, opts ...grpc.CallOption) (TodoService_UpdateTasksClient, error)
 	DeleteTasks(ctx context.Context, opts ...grpc.CallOption) (TodoService_DeleteTasksClient, error)
+	WatchTasks(ctx context.Context, in *WatchTasksRequest, opts ...grpc.CallOption) (TodoService_WatchTasksClient, error)
 }
 
 type todoServiceClient struct {
@@ -52,6 +58,24 @@ func (c *todoServiceClient) AddTask(ctx context.Context, in *AddTaskRequest, opt
 	return out, nil
 }
 
+func (c *todoServiceClient) AddTasks(ctx context.Context, in *AddTasksRequest, opts ...grpc.CallOption) (*AddTasksResponse, error) {
+	out := new(AddTasksResponse)
+	err := c.cc.Invoke(ctx, TodoService_AddTasks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetTask(ctx context.Context, in *GetTaskRequest, opts ...grpc.CallOption) (*GetTaskResponse, error) {
+	out := new(GetTaskResponse)
+	err := c.cc.Invoke(ctx, TodoService_GetTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *todoServiceClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (TodoService_ListTasksClient, error) {
 	stream, err := c.cc.NewStream(ctx, &TodoService_ServiceDesc.Streams[0], TodoService_ListTasks_FullMethodName, opts...)
 	if err != nil {
@@ -149,14 +173,49 @@ func (x *todoServiceDeleteTasksClient) Recv() (*DeleteTasksResponse, error) {
 	return m, nil
 }
 
+func (c *todoServiceClient) WatchTasks(ctx context.Context, in *WatchTasksRequest, opts ...grpc.CallOption) (TodoService_WatchTasksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TodoService_ServiceDesc.Streams[3], TodoService_WatchTasks_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &todoServiceWatchTasksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TodoService_WatchTasksClient interface {
+	Recv() (*WatchTasksResponse, error)
+	grpc.ClientStream
+}
+
+type todoServiceWatchTasksClient struct {
+	grpc.ClientStream
+}
+
+func (x *todoServiceWatchTasksClient) Recv() (*WatchTasksResponse, error) {
+	m := new(WatchTasksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // TodoServiceServer is the server API for TodoService service.
 // All implementations must embed UnimplementedTodoServiceServer
 // for forward compatibility
 type TodoServiceServer interface {
 	AddTask(context.Context, *AddTaskRequest) (*AddTaskResponse, error)
+	AddTasks(context.Context, *AddTasksRequest) (*AddTasksResponse, error)
+	GetTask(context.Context, *GetTaskRequest) (*GetTaskResponse, error)
 	ListTasks(*ListTasksRequest, TodoService_ListTasksServer) error
 	UpdateTasks(TodoService_UpdateTasksServer) error
 	DeleteTasks(TodoService_DeleteTasksServer) error
+	WatchTasks(*WatchTasksRequest, TodoService_WatchTasksServer) error
 	mustEmbedUnimplementedTodoServiceServer()
 }
 
@@ -167,6 +226,12 @@ type UnimplementedTodoServiceServer struct {
 func (UnimplementedTodoServiceServer) AddTask(context.Context, *AddTaskRequest) (*AddTaskResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddTask not implemented")
 }
+func (UnimplementedTodoServiceServer) AddTasks(context.Context, *AddTasksRequest) (*AddTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTasks not implemented")
+}
+func (UnimplementedTodoServiceServer) GetTask(context.Context, *GetTaskRequest) (*GetTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTask not implemented")
+}
 func (UnimplementedTodoServiceServer) ListTasks(*ListTasksRequest, TodoService_ListTasksServer) error {
 	return status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
 }
@@ -176,6 +241,9 @@ func (UnimplementedTodoServiceServer) UpdateTasks(TodoService_UpdateTasksServer)
 func (UnimplementedTodoServiceServer) DeleteTasks(TodoService_DeleteTasksServer) error {
 	return status.Errorf(codes.Unimplemented, "method DeleteTasks not implemented")
 }
+func (UnimplementedTodoServiceServer) WatchTasks(*WatchTasksRequest, TodoService_WatchTasksServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTasks not implemented")
+}
 func (UnimplementedTodoServiceServer) mustEmbedUnimplementedTodoServiceServer() {}
 
 // UnsafeTodoServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -207,6 +275,42 @@ func _TodoService_AddTask_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TodoService_AddTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).AddTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_AddTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).AddTasks(ctx, req.(*AddTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_GetTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetTask(ctx, req.(*GetTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TodoService_ListTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(ListTasksRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -280,6 +384,27 @@ func (x *todoServiceDeleteTasksServer) Recv() (*DeleteTasksRequest, error) {
 	return m, nil
 }
 
+func _TodoService_WatchTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTasksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoServiceServer).WatchTasks(m, &todoServiceWatchTasksServer{stream})
+}
+
+type TodoService_WatchTasksServer interface {
+	Send(*WatchTasksResponse) error
+	grpc.ServerStream
+}
+
+type todoServiceWatchTasksServer struct {
+	grpc.ServerStream
+}
+
+func (x *todoServiceWatchTasksServer) Send(m *WatchTasksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // TodoService_ServiceDesc is the grpc.ServiceDesc for TodoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -291,6 +416,14 @@ var TodoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddTask",
 			Handler:    _TodoService_AddTask_Handler,
 		},
+		{
+			MethodName: "AddTasks",
+			Handler:    _TodoService_AddTasks_Handler,
+		},
+		{
+			MethodName: "GetTask",
+			Handler:    _TodoService_GetTask_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -309,6 +442,11 @@ var TodoService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "WatchTasks",
+			Handler:       _TodoService_WatchTasks_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "todo/v2/todo.proto",
 }
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// errInvalidToken is wrapped by the errors of the authenticators
+// rejecting a token.
+var errInvalidToken = errors.New("invalid token")
+
+// Principal is the identity authenticated for a call.
+type Principal struct {
+	Subject string
+	// Claims are all the claims of the token, when it has some (JWT).
+	Claims map[string]any
+	// Peer is the identity of the client certificate, when it was
+	// verified with mTLS (see clientIdentity). Policies can bind roles
+	// to it (see policy).
+	Peer string
+}
+
+// Authenticator verifies the token sent by a client (see
+// validateAuthToken) and returns the principal it authenticates, which
+// must have a subject. Rejected tokens should be reported with an error
+// wrapping errInvalidToken, other errors are handled like the ones of
+// the db (see toStatus).
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+// AuthenticatorFunc is the hook for custom verifiers: it turns a
+// function into an Authenticator.
+type AuthenticatorFunc func(ctx context.Context, token string) (*Principal, error)
+
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	return f(ctx, token)
+}
+
+// authenticators build the Authenticator selected by the -auth flag,
+// from the file given by -auth-config. Custom verifiers are registered
+// from the init function of another file:
+//
+//	func init() {
+//		authenticators["ldap"] = newLDAPAuthenticator
+//	}
+var authenticators = map[string]func(config string) (Authenticator, error){
+	"static": newStaticAuthenticator,
+	"hmac":   newHMACAuthenticator,
+	"jwt": func(path string) (Authenticator, error) {
+		return newJWTAuthenticator(path, *jwtIssuer, *jwtAudience)
+	},
+	"mtls": newCertAuthenticator,
+}
+
+// newAuthenticator returns the Authenticator registered as name.
+func newAuthenticator(name, config string) (Authenticator, error) {
+	newAuth, ok := authenticators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown authenticator %q", name)
+	}
+	return newAuth(config)
+}
+
+// staticAuthenticator accepts a fixed set of tokens. Only their hashes
+// are kept, so that they are compared in constant time.
+type staticAuthenticator struct {
+	tokens []staticToken
+}
+
+type staticToken struct {
+	subject string
+	hash    [sha256.Size]byte
+}
+
+// newStaticAuthenticator reads the tokens from the file at path, see
+// parseStaticTokens.
+func newStaticAuthenticator(path string) (Authenticator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	a, err := parseStaticTokens(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return a, nil
+}
+
+// parseStaticTokens reads one "<subject> <token>" pair per line. Empty
+// lines and lines starting with # are ignored.
+func parseStaticTokens(r io.Reader) (*staticAuthenticator, error) {
+	a := &staticAuthenticator{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected \"<subject> <token>\"", n)
+		}
+		t := staticToken{subject: fields[0], hash: sha256.Sum256([]byte(fields[1]))}
+		for _, other := range a.tokens {
+			if other.hash == t.hash {
+				return nil, fmt.Errorf("line %d: duplicate token", n)
+			}
+		}
+		a.tokens = append(a.tokens, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(a.tokens) == 0 {
+		return nil, errors.New("no token")
+	}
+	return a, nil
+}
+
+func (a *staticAuthenticator) Authenticate(_ context.Context, token string) (*Principal, error) {
+	hash := sha256.Sum256([]byte(token))
+	var subject string
+	// every token is compared, not to leak which one matched
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(hash[:], t.hash[:]) == 1 {
+			subject = t.subject
+		}
+	}
+	if subject == "" {
+		return nil, errInvalidToken
+	}
+	return &Principal{Subject: subject}, nil
+}
+
+// minHMACKeySize is the minimum size of the keys of hmacAuthenticator,
+// the size of the HMAC-SHA256 output.
+const minHMACKeySize = sha256.Size
+
+// hmacAuthenticator accepts the tokens signed with its key by
+// signHMACToken, until they expire.
+type hmacAuthenticator struct {
+	key []byte
+	now func() time.Time
+}
+
+// hmacClaims are the content of the tokens of hmacAuthenticator.
+type hmacClaims struct {
+	Subject string `json:"sub"`
+	Expiry  int64  `json:"exp"` // unix seconds
+}
+
+// newHMACAuthenticator reads the key from the file at path, see
+// readHMACKey.
+func newHMACAuthenticator(path string) (Authenticator, error) {
+	key, err := readHMACKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return &hmacAuthenticator{key: key, now: time.Now}, nil
+}
+
+// readHMACKey returns the content of the file at path, without leading
+// and trailing white space.
+func readHMACKey(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key := bytes.TrimSpace(b)
+	if len(key) < minHMACKeySize {
+		return nil, fmt.Errorf("%s: hmac key should be at least %d bytes long", path, minHMACKeySize)
+	}
+	return key, nil
+}
+
+// signHMACToken returns a token for subject expiring at expiry: the
+// base64 of the JSON claims and the base64 of their HMAC-SHA256 with
+// key, separated by a dot.
+func signHMACToken(key []byte, subject string, expiry time.Time) (string, error) {
+	claims, err := json.Marshal(hmacClaims{Subject: subject, Expiry: expiry.Unix()})
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(hmacSum(key, payload)), nil
+}
+
+func hmacSum(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func (a *hmacAuthenticator) Authenticate(_ context.Context, token string) (*Principal, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed", errInvalidToken)
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", errInvalidToken)
+	}
+	// the claims are only decoded once they are known to be ours
+	if !hmac.Equal(got, hmacSum(a.key, payload)) {
+		return nil, fmt.Errorf("%w: bad signature", errInvalidToken)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", errInvalidToken)
+	}
+	var claims hmacClaims
+	if err := json.Unmarshal(b, &claims); err != nil || claims.Subject == "" || claims.Expiry == 0 {
+		return nil, fmt.Errorf("%w: malformed claims", errInvalidToken)
+	}
+	if !a.now().Before(time.Unix(claims.Expiry, 0)) {
+		return nil, fmt.Errorf("%w: expired", errInvalidToken)
+	}
+	return &Principal{Subject: claims.Subject}, nil
+}
+
+type principalKey struct{}
+
+// withPrincipal returns a copy of ctx carrying the principal
+// authenticated for the call.
+func withPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// principalFromContext returns the principal authenticated for the call
+// of ctx, if any.
+func principalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// ownerFromContext returns the owner of the tasks of the call of ctx,
+// the subject of its principal. The calls aren't authenticated without
+// the auth interceptors (in tests), their tasks have no owner.
+func ownerFromContext(ctx context.Context) string {
+	if p, ok := principalFromContext(ctx); ok {
+		return p.Subject
+	}
+	return ""
+}
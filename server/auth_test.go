@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseStaticTokens(t *testing.T) {
+	a, err := parseStaticTokens(strings.NewReader("# comment\n\nalice secret1\n  bob   secret2  \n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for token, subject := range map[string]string{"secret1": "alice", "secret2": "bob"} {
+		p, err := a.Authenticate(context.TODO(), token)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.Subject != subject {
+			t.Errorf("expected %q for %q, got %q", subject, token, p.Subject)
+		}
+	}
+	for _, token := range []string{"", "secret", "alice", "secret1 "} {
+		if _, err := a.Authenticate(context.TODO(), token); !errors.Is(err, errInvalidToken) {
+			t.Errorf("expected %v for %q, got %v", errInvalidToken, token, err)
+		}
+	}
+}
+
+func TestParseStaticTokensInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"Empty", "# no token\n"},
+		{"NoSubject", "secret\n"},
+		{"TooManyFields", "alice secret other\n"},
+		{"Duplicate", "alice secret\nbob secret\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseStaticTokens(strings.NewReader(tt.in)); err == nil {
+				t.Errorf("expected an error parsing %q", tt.in)
+			}
+		})
+	}
+}
+
+func TestHMACAuthenticator(t *testing.T) {
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	key := []byte(strings.Repeat("k", minHMACKeySize))
+	a := &hmacAuthenticator{key: key, now: func() time.Time { return now }}
+	sign := func(key []byte, subject string, expiry time.Time) string {
+		token, err := signHMACToken(key, subject, expiry)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return token
+	}
+
+	valid := sign(key, "alice", now.Add(time.Minute))
+	p, err := a.Authenticate(context.TODO(), valid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Subject != "alice" {
+		t.Errorf("expected alice, got %q", p.Subject)
+	}
+
+	payload, _, _ := strings.Cut(valid, ".")
+	signed := func(claims string) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(claims))
+		return payload + "." + base64.RawURLEncoding.EncodeToString(hmacSum(key, payload))
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Expired", sign(key, "alice", now)},
+		{"OtherKey", sign([]byte(strings.Repeat("o", minHMACKeySize)), "alice", now.Add(time.Minute))},
+		{"Empty", ""},
+		{"NoSignature", payload},
+		{"MalformedSignature", payload + ".!!!"},
+		{"TamperedClaims", base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"bob","exp":9999999999}`)) + valid[len(payload):]},
+		{"MalformedClaims", signed("not json")},
+		{"NoSubject", signed(`{"exp":9999999999}`)},
+		{"NoExpiry", signed(`{"sub":"alice"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := a.Authenticate(context.TODO(), tt.token); !errors.Is(err, errInvalidToken) {
+				t.Errorf("expected %v, got %v", errInvalidToken, err)
+			}
+		})
+	}
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	dir := t.TempDir()
+	tokens := filepath.Join(dir, "tokens")
+	if err := os.WriteFile(tokens, []byte("alice secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	shortKey := filepath.Join(dir, "short_key")
+	if err := os.WriteFile(shortKey, []byte("short\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	key := filepath.Join(dir, "key")
+	if err := os.WriteFile(key, []byte(strings.Repeat("k", minHMACKeySize)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newAuthenticator("static", tokens); err != nil {
+		t.Errorf("static: unexpected error: %v", err)
+	}
+	if _, err := newAuthenticator("static", filepath.Join(dir, "missing")); err == nil {
+		t.Error("static: expected an error for a missing file")
+	}
+	if _, err := newAuthenticator("hmac", key); err != nil {
+		t.Errorf("hmac: unexpected error: %v", err)
+	}
+	if _, err := newAuthenticator("hmac", shortKey); err == nil {
+		t.Error("hmac: expected an error for a short key")
+	}
+	if _, err := newAuthenticator("unknown", tokens); err == nil {
+		t.Error("expected an error for an unknown authenticator")
+	}
+}
+
+func TestValidateAuthToken(t *testing.T) {
+	a := AuthenticatorFunc(func(_ context.Context, token string) (*Principal, error) {
+		switch token {
+		case "alice":
+			return &Principal{Subject: "alice"}, nil
+		case "unavailable":
+			return nil, errUnavailable
+		case "nil":
+			return nil, nil
+		case "anonymous":
+			return &Principal{}, nil
+		}
+		return nil, errInvalidToken
+	})
+	tests := []struct {
+		name string
+		md   metadata.MD
+		code codes.Code
+	}{
+		{"Valid", metadata.Pairs(authTokenKey, "alice"), codes.OK},
+		{"NoMetadata", nil, codes.Unauthenticated},
+		{"NoToken", metadata.Pairs("other", "alice"), codes.Unauthenticated},
+		{"Invalid", metadata.Pairs(authTokenKey, "bob"), codes.Unauthenticated},
+		{"MultiValued", metadata.Pairs(authTokenKey, "alice", authTokenKey, "alice"), codes.InvalidArgument},
+		{"Bearer", metadata.Pairs(authorizationKey, "Bearer alice"), codes.OK},
+		{"BearerPreferred", metadata.Pairs(authorizationKey, "bearer alice", authTokenKey, "bob"), codes.OK},
+		{"BearerInvalid", metadata.Pairs(authorizationKey, "Bearer bob"), codes.Unauthenticated},
+		{"BearerMultiValued", metadata.Pairs(authorizationKey, "Bearer alice", authorizationKey, "Bearer alice"), codes.InvalidArgument},
+		{"OtherScheme", metadata.Pairs(authorizationKey, "Basic alice"), codes.Unauthenticated},
+		{"NoScheme", metadata.Pairs(authorizationKey, "alice"), codes.Unauthenticated},
+		{"Unavailable", metadata.Pairs(authTokenKey, "unavailable"), codes.Unavailable},
+		{"NoPrincipal", metadata.Pairs(authTokenKey, "nil"), codes.Internal},
+		{"NoSubject", metadata.Pairs(authTokenKey, "anonymous"), codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			ctx, err := validateAuthToken(ctx, a)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+			if err != nil {
+				return
+			}
+			if p, ok := principalFromContext(ctx); !ok || p.Subject != "alice" {
+				t.Errorf("expected alice in context, got %v", p)
+			}
+		})
+	}
+}
@@ -0,0 +1,482 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// boltBatchSize is the number of tasks getTasks reads per transaction.
+const boltBatchSize = 64
+
+var (
+	tasksBucket = []byte("tasks")
+	// eventsBucket is the change feed, keyed by revision. Values are the
+	// eventKind byte followed by the marshalled pb.Task.
+	eventsBucket = []byte("events")
+)
+
+// sortIndexes are the buckets indexing the tasks by the fields other
+// than id they can be sorted by. Their keys are the sort keys of the
+// tasks (see sortKey), their values are empty.
+var sortIndexes = map[orderField][]byte{
+	orderByDueDate:     []byte("tasks_by_due_date"),
+	orderByDescription: []byte("tasks_by_description"),
+	orderByDone:        []byte("tasks_by_done"),
+}
+
+// maxIndexedDescription is the length of the prefix of the descriptions
+// kept in the sort keys, bbolt keys can't be longer than 32KiB. Tasks
+// whose descriptions share this prefix are sorted by id among
+// themselves.
+const maxIndexedDescription = 1024
+
+// boltDB stores tasks as marshalled pb.Task in a single file, keyed by
+// their big-endian encoded id so that keys are sorted by id. The other
+// orders are read from the sortIndexes buckets.
+type boltDB struct {
+	db      *bolt.DB
+	changes notifier
+}
+
+// newBoltDB opens, or creates, the bbolt database at path.
+func newBoltDB(path string) (*boltDB, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{tasksBucket, eventsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return createSortIndexes(tx)
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltDB{db: db}, nil
+}
+
+func (d *boltDB) Close() error {
+	return d.db.Close()
+}
+
+// createSortIndexes creates the missing sortIndexes buckets, indexing
+// the tasks stored before they were introduced.
+func createSortIndexes(tx *bolt.Tx) error {
+	for field, name := range sortIndexes {
+		if tx.Bucket(name) != nil {
+			continue
+		}
+		index, err := tx.CreateBucket(name)
+		if err != nil {
+			return err
+		}
+		err = tx.Bucket(tasksBucket).ForEach(func(_, v []byte) error {
+			var task pb.Task
+			if err := proto.Unmarshal(v, &task); err != nil {
+				return err
+			}
+			return index.Put(sortKey(field, &task), []byte{})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortKey returns the key of task sorted by field: the value of field,
+// encoded so that the keys sort bytewise like taskOrder.compare, then
+// the id breaking ties. Sorted by id, it is the key of the task in
+// tasksBucket.
+func sortKey(field orderField, task *pb.Task) []byte {
+	var k []byte
+	switch field {
+	case orderByDueDate:
+		// tasks without due date come last, the sign bit of the seconds
+		// is flipped for the negative ones to come first.
+		if task.DueDate == nil {
+			k = append(k, 1)
+		} else {
+			k = append(k, 0)
+			k = binary.BigEndian.AppendUint64(k, uint64(task.DueDate.Seconds)^1<<63)
+			k = binary.BigEndian.AppendUint32(k, uint32(task.DueDate.Nanos))
+		}
+	case orderByDescription:
+		// 0 bytes are escaped and the description terminated, so that
+		// it comes before the longer ones it is a prefix of.
+		desc := task.Description
+		if len(desc) > maxIndexedDescription {
+			desc = desc[:maxIndexedDescription]
+		}
+		for i := 0; i < len(desc); i++ {
+			if desc[i] == 0 {
+				k = append(k, 0, 0xff)
+			} else {
+				k = append(k, desc[i])
+			}
+		}
+		k = append(k, 0, 1)
+	case orderByDone:
+		if task.Done {
+			k = append(k, 1)
+		} else {
+			k = append(k, 0)
+		}
+	}
+	return binary.BigEndian.AppendUint64(k, task.Id)
+}
+
+// indexTask adds task to the sortIndexes.
+func indexTask(tx *bolt.Tx, task *pb.Task) error {
+	for field, name := range sortIndexes {
+		if err := tx.Bucket(name).Put(sortKey(field, task), []byte{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unindexTask removes task from the sortIndexes, it must be called with
+// the task as it was indexed.
+func unindexTask(tx *bolt.Tx, task *pb.Task) error {
+	for field, name := range sortIndexes {
+		if err := tx.Bucket(name).Delete(sortKey(field, task)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func itob(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
+
+func putTask(b *bolt.Bucket, task *pb.Task) error {
+	out, err := proto.Marshal(task)
+	if err != nil {
+		return err
+	}
+	return b.Put(itob(task.Id), out)
+}
+
+func getTask(b *bolt.Bucket, id uint64) (*pb.Task, error) {
+	v := b.Get(itob(id))
+	if v == nil {
+		return nil, taskNotFound(id)
+	}
+	var task pb.Task
+	if err := proto.Unmarshal(v, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (d *boltDB) addTask(ctx context.Context, owner, description string, dueDate time.Time) (uint64, error) {
+	ids, err := d.addTasks(ctx, []newTask{{owner, description, dueDate}})
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+func (d *boltDB) addTasks(ctx context.Context, tasks []newTask) ([]uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var ids []uint64
+	err := d.write(func(tx *bolt.Tx) error {
+		b := tx.Bucket(tasksBucket)
+		ids = make([]uint64, 0, len(tasks))
+		for _, t := range tasks {
+			// the sequence is stored in the bucket, ids are never reused
+			// even after a restart.
+			id, err := b.NextSequence()
+			if err != nil {
+				return err
+			}
+			task := &pb.Task{
+				Id:          id,
+				Description: t.description,
+				DueDate:     timestamppb.New(t.dueDate),
+				Version:     1,
+				Owner:       t.owner,
+			}
+			if err := putTask(b, task); err != nil {
+				return err
+			}
+			if err := indexTask(tx, task); err != nil {
+				return err
+			}
+			if err := recordEvent(tx, eventCreated, task); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (d *boltDB) getTask(ctx context.Context, id uint64) (*pb.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var task *pb.Task
+	err := d.db.View(func(tx *bolt.Tx) error {
+		var err error
+		task, err = getTask(tx.Bucket(tasksBucket), id)
+		return err
+	})
+	return task, err
+}
+
+// getTasks calls f for every task selected by q, in q.order.
+//
+// The tasks are read boltBatchSize at a time, from tasksBucket sorted by
+// id or from the index of q.order otherwise, and f is called outside of
+// any transaction, so a slow f neither holds the whole list in memory
+// nor keeps a long-running transaction open (which would block writers
+// whenever the file needs to grow). Each batch is consistent, but tasks
+// added or updated after the position of the iteration will be visible
+// to f.
+//
+// Tasks are unmarshalled to be matched, a batch holds up to
+// boltBatchSize matching tasks.
+func (d *boltDB) getTasks(ctx context.Context, q taskQuery, f func(any) error) error {
+	var after []byte
+	if q.after != nil {
+		after = sortKey(q.order.field, q.after)
+	}
+	remaining := q.limit
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		size := boltBatchSize
+		if q.limit > 0 && remaining < size {
+			size = remaining
+		}
+		batch := make([]*pb.Task, 0, size)
+		last := true
+		err := d.db.View(func(tx *bolt.Tx) error {
+			tasks := tx.Bucket(tasksBucket)
+			c := tasks.Cursor()
+			if q.order.field != orderByID {
+				c = tx.Bucket(sortIndexes[q.order.field]).Cursor()
+			}
+			next := c.Next
+			if q.order.desc {
+				next = c.Prev
+			}
+			for k, v := seekAfter(c, after, q.order.desc); k != nil; k, v = next() {
+				if len(batch) == size {
+					last = false
+					return nil
+				}
+				if q.order.field != orderByID {
+					// the id ends the sort key
+					v = tasks.Get(k[len(k)-8:])
+				}
+				var task pb.Task
+				if err := proto.Unmarshal(v, &task); err != nil {
+					return err
+				}
+				// k is only valid during the transaction
+				after = append(after[:0], k...)
+				if q.match(&task) {
+					batch = append(batch, &task)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := yieldTasks(ctx, batch, f); err != nil {
+			return err
+		}
+		remaining -= len(batch)
+		if last || (q.limit > 0 && remaining == 0) {
+			return nil
+		}
+	}
+}
+
+// seekAfter moves c to the first key coming after the given one, in
+// descending order if desc. The first key is returned if after is nil.
+func seekAfter(c *bolt.Cursor, after []byte, desc bool) (k, v []byte) {
+	switch {
+	case after == nil && desc:
+		return c.Last()
+	case after == nil:
+		return c.First()
+	}
+
+	k, v = c.Seek(after) // first key >= after
+	switch {
+	case desc && k == nil:
+		return c.Last()
+	case desc:
+		return c.Prev()
+	case bytes.Equal(k, after):
+		return c.Next()
+	default:
+		return k, v
+	}
+}
+
+// yieldTasks calls f for every task, until ctx is done.
+func yieldTasks(ctx context.Context, tasks []*pb.Task, f func(any) error) error {
+	for _, task := range tasks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := f(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *boltDB) updateTask(ctx context.Context, id uint64, update *pb.Task, mask *fieldmaskpb.FieldMask, pre precondition) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.write(func(tx *bolt.Tx) error {
+		b := tx.Bucket(tasksBucket)
+		task, err := getTask(b, id)
+		if err != nil {
+			return err
+		}
+		if err := pre.check(task); err != nil {
+			return err
+		}
+		if err := unindexTask(tx, task); err != nil {
+			return err
+		}
+		applyUpdate(task, update, mask)
+		if err := putTask(b, task); err != nil {
+			return err
+		}
+		if err := indexTask(tx, task); err != nil {
+			return err
+		}
+		return recordEvent(tx, eventUpdated, task)
+	})
+}
+
+func (d *boltDB) deleteTask(ctx context.Context, id uint64, pre precondition) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.write(func(tx *bolt.Tx) error {
+		b := tx.Bucket(tasksBucket)
+		task, err := getTask(b, id)
+		if err != nil {
+			return err
+		}
+		if err := pre.check(task); err != nil {
+			return err
+		}
+		if err := b.Delete(itob(id)); err != nil {
+			return err
+		}
+		if err := unindexTask(tx, task); err != nil {
+			return err
+		}
+		return recordEvent(tx, eventDeleted, task)
+	})
+}
+
+// write runs fn in a read-write transaction and wakes up the watchers
+// once it is committed.
+func (d *boltDB) write(fn func(tx *bolt.Tx) error) error {
+	if err := d.db.Update(fn); err != nil {
+		return err
+	}
+	d.changes.notify()
+	return nil
+}
+
+// recordEvent appends a change of task to the change feed, discarding
+// the changes older than eventRetention.
+func recordEvent(tx *bolt.Tx, kind eventKind, task *pb.Task) error {
+	b := tx.Bucket(eventsBucket)
+	rev, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+	out, err := proto.Marshal(task)
+	if err != nil {
+		return err
+	}
+	if err := b.Put(itob(rev), append([]byte{byte(kind)}, out...)); err != nil {
+		return err
+	}
+	if rev > eventRetention {
+		return b.Delete(itob(rev - eventRetention))
+	}
+	return nil
+}
+
+// watchTasks doesn't poll, the file can only be opened by one process.
+func (d *boltDB) watchTasks(ctx context.Context, since uint64, f func(taskEvent) error) error {
+	return watchChanges(ctx, d, &d.changes, 0, since, f)
+}
+
+func (d *boltDB) lastRevision(ctx context.Context) (uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	var rev uint64
+	err := d.db.View(func(tx *bolt.Tx) error {
+		rev = tx.Bucket(eventsBucket).Sequence()
+		return nil
+	})
+	return rev, err
+}
+
+func (d *boltDB) eventsAfter(ctx context.Context, rev uint64) ([]taskEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var events []taskEvent
+	err := d.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(eventsBucket).Cursor()
+		for k, v := c.Seek(itob(rev + 1)); k != nil && len(events) < eventBatchSize; k, v = c.Next() {
+			if len(v) == 0 {
+				return fmt.Errorf("invalid event %d", binary.BigEndian.Uint64(k))
+			}
+			e := taskEvent{
+				revision: binary.BigEndian.Uint64(k),
+				kind:     eventKind(v[0]),
+				task:     &pb.Task{},
+			}
+			if err := proto.Unmarshal(v[1:], e.task); err != nil {
+				return err
+			}
+			events = append(events, e)
+		}
+		return nil
+	})
+	return events, err
+}
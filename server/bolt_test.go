@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestBoltDB(t *testing.T, path string) *boltDB {
+	t.Helper()
+	d, err := newBoltDB(path)
+	if err != nil {
+		t.Fatalf("failed opening bolt database: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func TestBoltDB(t *testing.T) {
+	testDB(t, func(t *testing.T) db {
+		return newTestBoltDB(t, filepath.Join(t.TempDir(), "todo.db"))
+	})
+	t.Run("Reopen", testBoltReopen)
+	t.Run("GetTasksBatches", testBoltGetTasksBatches)
+	t.Run("SortIndexesCreated", testBoltSortIndexesCreated)
+}
+
+func TestSortKey(t *testing.T) {
+	epoch := time.Unix(0, 0)
+	var tasks []*pb.Task
+	for i, dueDate := range []*timestamppb.Timestamp{
+		nil,
+		timestamppb.New(epoch.Add(-time.Hour)),
+		timestamppb.New(epoch.Add(-time.Nanosecond)),
+		timestamppb.New(epoch),
+		timestamppb.New(epoch.Add(time.Nanosecond)),
+		timestamppb.New(epoch.Add(time.Second)),
+	} {
+		for j, description := range []string{"", "a", "a\x00", "a\x00b", "a\x01", "ab", "b"} {
+			for _, done := range []bool{false, true} {
+				tasks = append(tasks, &pb.Task{
+					Id:          uint64(1 + (i*7+j)%5), // with ties
+					DueDate:     dueDate,
+					Description: description,
+					Done:        done,
+				})
+			}
+		}
+	}
+
+	for _, field := range []orderField{orderByID, orderByDueDate, orderByDescription, orderByDone} {
+		t.Run(field.String(), func(t *testing.T) {
+			o := taskOrder{field: field}
+			for _, a := range tasks {
+				for _, b := range tasks {
+					expected := o.compare(a, b)
+					got := bytes.Compare(sortKey(field, a), sortKey(field, b))
+					if (expected < 0) != (got < 0) || (expected > 0) != (got > 0) {
+						t.Fatalf("expected the keys of %v and %v to compare as %d, got %d", a, b, expected, got)
+					}
+				}
+			}
+		})
+	}
+
+	long := &pb.Task{Description: strings.Repeat("\x00", bolt.MaxKeySize)}
+	if n := len(sortKey(orderByDescription, long)); n > bolt.MaxKeySize {
+		t.Errorf("expected a key of at most %d bytes, got %d", bolt.MaxKeySize, n)
+	}
+}
+
+func testBoltReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.db")
+	d := newTestBoltDB(t, path)
+	ids := addTasks(t, d, 2)
+	if err := d.deleteTask(context.TODO(), ids[1], precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Close()
+
+	d = newTestBoltDB(t, path)
+	tasks := listTasks(t, d)
+	if len(tasks) != 1 || tasks[0].Id != ids[0] {
+		t.Fatalf("expected task %d, got %v", ids[0], tasks)
+	}
+	id, err := d.addTask(context.TODO(), "", "after restart", tasks[0].DueDate.AsTime())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id <= ids[1] {
+		t.Errorf("id %d reused after restart", id)
+	}
+}
+
+func testBoltGetTasksBatches(t *testing.T) {
+	d := newTestBoltDB(t, filepath.Join(t.TempDir(), "todo.db"))
+	ids := addTasks(t, d, 2*boltBatchSize+1)
+
+	// deleting the next task while reading the end of a batch should
+	// not stop or break the iteration.
+	var seen []uint64
+	err := d.getTasks(context.TODO(), taskQuery{}, func(a any) error {
+		task := a.(*pb.Task)
+		seen = append(seen, task.Id)
+		if task.Id == ids[boltBatchSize-1] {
+			return d.deleteTask(context.TODO(), ids[boltBatchSize], precondition{})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(seen) != len(ids)-1 {
+		t.Fatalf("expected %d tasks, got %d", len(ids)-1, len(seen))
+	}
+	for i, id := range seen {
+		if i > 0 && id <= seen[i-1] {
+			t.Fatalf("tasks not in id order: %v", seen)
+		}
+		if id == ids[boltBatchSize] {
+			t.Errorf("deleted task %d was returned", id)
+		}
+	}
+}
+
+// testBoltSortIndexesCreated checks the tasks of files created before
+// the sort indexes are indexed when they are opened.
+func testBoltSortIndexesCreated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.db")
+	d := newTestBoltDB(t, path)
+	ids := seedDB(t, d,
+		&pb.Task{Description: "b"},
+		&pb.Task{Description: "a"},
+	)
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range sortIndexes {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Close()
+
+	d = newTestBoltDB(t, path)
+	expected := []uint64{ids[1], ids[0]}
+	if got := queryIDs(t, d, taskQuery{order: taskOrder{field: orderByDescription}}); !slices.Equal(got, expected) {
+		t.Errorf("expected tasks %v, got %v", expected, got)
+	}
+}
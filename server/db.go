@@ -1,10 +1,110 @@
 package main
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"time"
 
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// db is the storage of the tasks. Every method should give up and
+// return ctx.Err() as soon as ctx is done, getTasks included in between
+// two calls to f.
+//
+// Tasks belong to the owner they are added for, which never changes.
+//
+// addTasks adds all the tasks or none of them, and returns their ids in
+// order.
+//
+// getTasks calls f with the tasks selected by q, in q.order.
+//
+// updateTask only changes the fields listed in mask, to the values they
+// have in update (see applyUpdate). Tasks are added with version 1 and
+// every update increments it. updateTask and deleteTask leave the task
+// unchanged if it doesn't satisfy pre (see precondition.check).
+//
+// Every write is recorded in a change feed, with a revision increasing
+// with every change. watchTasks calls f with the changes after since,
+// or with the ones happening after the call if since is 0, in order,
+// until ctx is done or f returns an error. Only the last eventRetention
+// changes are kept, errCompacted is returned if some of the changes
+// after since were discarded, or if since is after the last change.
 type db interface {
-	addTask(description string, dueDate time.Time) (uint64, error)
-	getTasks(f func(any) error) error
-	updateTask(id uint64, description string, dueDate time.Time, done bool) error
-	deleteTask(id uint64) error
+	addTask(ctx context.Context, owner, description string, dueDate time.Time) (uint64, error)
+	addTasks(ctx context.Context, tasks []newTask) ([]uint64, error)
+	getTask(ctx context.Context, id uint64) (*pb.Task, error)
+	getTasks(ctx context.Context, q taskQuery, f func(any) error) error
+	updateTask(ctx context.Context, id uint64, update *pb.Task, mask *fieldmaskpb.FieldMask, pre precondition) error
+	deleteTask(ctx context.Context, id uint64, pre precondition) error
+	watchTasks(ctx context.Context, since uint64, f func(taskEvent) error) error
+}
+
+// newTask is a task to add with addTasks.
+type newTask struct {
+	owner       string
+	description string
+	dueDate     time.Time
+}
+
+// precondition is what updateTask and deleteTask require of the task
+// before changing it.
+type precondition struct {
+	// owner is the owner the task should belong to.
+	owner string
+	// version, if not 0, is the version the task should have.
+	version uint64
+}
+
+// check returns errPermissionDenied if task doesn't belong to p.owner,
+// or errVersionMismatch if p.version isn't 0 and task has another
+// version.
+func (p precondition) check(task *pb.Task) error {
+	if task.Owner != p.owner {
+		return &taskError{id: task.Id, err: errPermissionDenied}
+	}
+	if p.version != 0 && task.Version != p.version {
+		return &taskError{id: task.Id, err: errVersionMismatch}
+	}
+	return nil
+}
+
+// taskQuery selects the tasks listed by getTasks. Backends should apply
+// it as close to the data as they allow.
+type taskQuery struct {
+	// owner is the owner of the tasks selected.
+	owner string
+	// filter only keeps the tasks it matches (see taskFilter.match).
+	filter taskFilter
+	order  taskOrder
+	// after, if set, only keeps the tasks coming after it in order.
+	// Only its id and the field sorted by are used, it doesn't need to
+	// be stored anymore.
+	after *pb.Task
+	// limit, if not 0, is the maximum number of tasks listed.
+	limit int
+}
+
+// match reports whether task is selected by q, regardless of q.after
+// and q.limit.
+func (q taskQuery) match(task *pb.Task) bool {
+	return task.Owner == q.owner && q.filter.match(task)
+}
+
+// openDB returns the db for the given storage backend. source is the
+// location of the data, its meaning depends on the backend.
+func openDB(ctx context.Context, storage, source string) (db, error) {
+	switch storage {
+	case "memory":
+		return New(), nil
+	case "sqlite":
+		return newSQLiteDB(source)
+	case "bolt":
+		return newBoltDB(source)
+	case "postgres":
+		return newPostgresDB(ctx, source)
+	default:
+		return nil, fmt.Errorf("unknown storage %q", storage)
+	}
 }
@@ -0,0 +1,938 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// testDB runs the tests every db implementation should pass.
+// newDB is called once per test and should return an empty database.
+func testDB(t *testing.T, newDB func(t *testing.T) db) {
+	t.Run("AddTask", func(t *testing.T) { testDBAddTask(t, newDB(t)) })
+	t.Run("AddTasks", func(t *testing.T) { testDBAddTasks(t, newDB(t)) })
+	t.Run("GetTask", func(t *testing.T) { testDBGetTask(t, newDB(t)) })
+	t.Run("UpdateTask", func(t *testing.T) { testDBUpdateTask(t, newDB(t)) })
+	t.Run("PartialUpdate", func(t *testing.T) { testDBPartialUpdate(t, newDB(t)) })
+	t.Run("Versions", func(t *testing.T) { testDBVersions(t, newDB(t)) })
+	t.Run("Owners", func(t *testing.T) { testDBOwners(t, newDB(t)) })
+	t.Run("DeleteTask", func(t *testing.T) { testDBDeleteTask(t, newDB(t)) })
+	t.Run("UniqueIDs", func(t *testing.T) { testDBUniqueIDs(t, newDB(t)) })
+	t.Run("ConcurrentUniqueIDs", func(t *testing.T) { testDBConcurrentUniqueIDs(t, newDB(t)) })
+	t.Run("Filter", func(t *testing.T) { testDBFilter(t, newDB(t)) })
+	t.Run("FilterBatches", func(t *testing.T) { testDBFilterBatches(t, newDB(t)) })
+	t.Run("Pages", func(t *testing.T) { testDBPages(t, newDB(t)) })
+	t.Run("Order", func(t *testing.T) { testDBOrder(t, newDB(t)) })
+	t.Run("Watch", func(t *testing.T) { testDBWatch(t, newDB(t)) })
+	t.Run("WatchCancelled", func(t *testing.T) { testDBWatchCancelled(t, newDB(t)) })
+	t.Run("WatchAhead", func(t *testing.T) { testDBWatchAhead(t, newDB(t)) })
+	t.Run("WatchStoredTasks", func(t *testing.T) { testDBWatchStoredTasks(t, newDB(t)) })
+	t.Run("GetTasksReturnsCopies", func(t *testing.T) { testDBGetTasksReturnsCopies(t, newDB(t)) })
+	t.Run("ConcurrentAccess", func(t *testing.T) { testDBConcurrentAccess(t, newDB(t)) })
+	t.Run("CancelledContext", func(t *testing.T) { testDBCancelledContext(t, newDB(t)) })
+	t.Run("CancelledWhileListing", func(t *testing.T) { testDBCancelledWhileListing(t, newDB(t)) })
+}
+
+func listTasks(t *testing.T, d db) []*pb.Task {
+	t.Helper()
+	var tasks []*pb.Task
+	err := d.getTasks(context.TODO(), taskQuery{}, func(a any) error {
+		tasks = append(tasks, a.(*pb.Task))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return tasks
+}
+
+func addTasks(t *testing.T, d db, n int) []uint64 {
+	t.Helper()
+	ids := make([]uint64, 0, n)
+	for i := 0; i < n; i++ {
+		id, err := d.addTask(context.TODO(), "", fmt.Sprintf("task %d", i), time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// fullUpdate returns an update of every field of defaultUpdateMask.
+func fullUpdate(description string, dueDate time.Time, done bool) *pb.Task {
+	return &pb.Task{
+		Description: description,
+		DueDate:     timestamppb.New(dueDate),
+		Done:        done,
+	}
+}
+
+func testDBAddTask(t *testing.T, d db) {
+	dueDate := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+	id, err := d.addTask(context.TODO(), "", "test", dueDate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tasks := listTasks(t, d)
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+	task := tasks[0]
+	if task.Id != id || task.Description != "test" || task.Done {
+		t.Errorf("unexpected task: %v", task)
+	}
+	if !task.DueDate.AsTime().Equal(dueDate) {
+		t.Errorf("expected due date %v, got %v", dueDate, task.DueDate.AsTime())
+	}
+}
+
+func testDBAddTasks(t *testing.T, d db) {
+	dueDate := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+	first := addTasks(t, d, 1)
+	ids, err := d.addTasks(context.TODO(), []newTask{
+		{description: "first", dueDate: dueDate},
+		{description: "second", dueDate: dueDate.Add(time.Hour)},
+		{description: "third", dueDate: dueDate},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 3 {
+		t.Fatalf("expected 3 ids, got %v", ids)
+	}
+
+	tasks := listTasks(t, d)
+	if len(tasks) != 4 {
+		t.Fatalf("expected 4 tasks, got %d", len(tasks))
+	}
+	for i, description := range []string{"first", "second", "third"} {
+		task := tasks[i+1]
+		if task.Id != ids[i] || task.Id <= first[0] || task.Description != description {
+			t.Errorf("expected task %d to be %q, got %v", ids[i], description, task)
+		}
+	}
+	if !tasks[2].DueDate.AsTime().Equal(dueDate.Add(time.Hour)) {
+		t.Errorf("expected due date %v, got %v", dueDate.Add(time.Hour), tasks[2].DueDate.AsTime())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.addTasks(ctx, []newTask{{description: "cancelled", dueDate: dueDate}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if n := len(listTasks(t, d)); n != 4 {
+		t.Errorf("expected no task to be added, got %d tasks", n)
+	}
+}
+
+func testDBGetTask(t *testing.T, d db) {
+	ids := addTasks(t, d, 2)
+	task, err := d.getTask(context.TODO(), ids[1])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Id != ids[1] || task.Description != "task 1" || task.DueDate == nil {
+		t.Errorf("expected task %d, got %v", ids[1], task)
+	}
+
+	// GetTask clears the fields filtered out by the mask
+	task.Description = ""
+	if task, err = d.getTask(context.TODO(), ids[1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Description != "task 1" {
+		t.Errorf("stored task was modified: %v", task)
+	}
+
+	if err := d.deleteTask(context.TODO(), ids[0], precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := d.getTask(context.TODO(), ids[0]); !errors.Is(err, errNotFound) {
+		t.Errorf("expected %v getting deleted task, got %v", errNotFound, err)
+	}
+	if _, err := d.getTask(context.TODO(), ids[1]+100); !errors.Is(err, errNotFound) {
+		t.Errorf("expected %v getting unknown task, got %v", errNotFound, err)
+	}
+}
+
+func testDBUpdateTask(t *testing.T, d db) {
+	ids := addTasks(t, d, 2)
+	dueDate := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Microsecond)
+	if err := d.updateTask(context.TODO(), ids[1], fullUpdate("updated", dueDate, true), defaultUpdateMask(), precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.updateTask(context.TODO(), ids[1]+1, fullUpdate("missing", dueDate, true), defaultUpdateMask(), precondition{}); !errors.Is(err, errNotFound) {
+		t.Errorf("expected %v updating missing task, got %v", errNotFound, err)
+	}
+
+	tasks := listTasks(t, d)
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(tasks))
+	}
+	if tasks[0].Description != "task 0" || tasks[0].Done {
+		t.Errorf("unexpected update of task: %v", tasks[0])
+	}
+	task := tasks[1]
+	if task.Description != "updated" || !task.Done || !task.DueDate.AsTime().Equal(dueDate) {
+		t.Errorf("task not updated: %v", task)
+	}
+}
+
+func testDBPartialUpdate(t *testing.T, d db) {
+	ids := addTasks(t, d, 1)
+	before := listTasks(t, d)[0]
+
+	// only done is updated, the empty description is ignored
+	update := &pb.Task{Done: true}
+	if err := d.updateTask(context.TODO(), ids[0], update, &fieldmaskpb.FieldMask{Paths: []string{"done"}}, precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := listTasks(t, d)[0]
+	if !task.Done || task.Description != before.Description || !task.DueDate.AsTime().Equal(before.DueDate.AsTime()) {
+		t.Errorf("expected only done to be updated, got %v", task)
+	}
+
+	// fields in the mask but not in the update are cleared
+	if err := d.updateTask(context.TODO(), ids[0], &pb.Task{}, &fieldmaskpb.FieldMask{Paths: []string{"due_date"}}, precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task = listTasks(t, d)[0]
+	if task.DueDate != nil || !task.Done || task.Description != before.Description {
+		t.Errorf("expected only due_date to be cleared, got %v", task)
+	}
+}
+
+func testDBVersions(t *testing.T, d db) {
+	ids := addTasks(t, d, 1)
+	version := func() uint64 {
+		t.Helper()
+		task, err := d.getTask(context.TODO(), ids[0])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return task.Version
+	}
+	if v := version(); v != 1 {
+		t.Fatalf("expected version 1 after add, got %d", v)
+	}
+
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"done"}}
+	if err := d.updateTask(context.TODO(), ids[0], &pb.Task{Done: true}, mask, precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.updateTask(context.TODO(), ids[0], &pb.Task{}, mask, precondition{version: 1}); !errors.Is(err, errVersionMismatch) {
+		t.Errorf("expected %v updating stale version, got %v", errVersionMismatch, err)
+	}
+	if v := version(); v != 2 {
+		t.Fatalf("expected version 2 after a failed update, got %d", v)
+	}
+	if err := d.updateTask(context.TODO(), ids[0], &pb.Task{}, mask, precondition{version: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := version(); v != 3 {
+		t.Fatalf("expected version 3, got %d", v)
+	}
+
+	if err := d.deleteTask(context.TODO(), ids[0], precondition{version: 2}); !errors.Is(err, errVersionMismatch) {
+		t.Errorf("expected %v deleting stale version, got %v", errVersionMismatch, err)
+	}
+	if task := listTasks(t, d); len(task) != 1 || task[0].Done {
+		t.Errorf("expected task to be left untouched, got %v", task)
+	}
+	if err := d.deleteTask(context.TODO(), ids[0], precondition{version: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func testDBOwners(t *testing.T, d db) {
+	dueDate := time.Now().Add(time.Hour)
+	ids, err := d.addTasks(context.TODO(), []newTask{
+		{owner: "alice", description: "alice 1", dueDate: dueDate},
+		{owner: "bob", description: "bob 1", dueDate: dueDate},
+		{owner: "alice", description: "alice 2", dueDate: dueDate},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := d.addTask(context.TODO(), "bob", "bob 2", dueDate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task, err := d.getTask(context.TODO(), ids[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Owner != "alice" {
+		t.Errorf("expected owner alice, got %q", task.Owner)
+	}
+
+	list := func(q taskQuery) []string {
+		t.Helper()
+		var descriptions []string
+		err := d.getTasks(context.TODO(), q, func(a any) error {
+			descriptions = append(descriptions, a.(*pb.Task).Description)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return descriptions
+	}
+	tests := []struct {
+		name     string
+		q        taskQuery
+		expected []string
+	}{
+		{"Alice", taskQuery{owner: "alice"}, []string{"alice 1", "alice 2"}},
+		{"Bob", taskQuery{owner: "bob", order: taskOrder{field: orderByDescription, desc: true}}, []string{"bob 2", "bob 1"}},
+		{"Filtered", taskQuery{owner: "alice", filter: taskFilter{description: "2"}}, []string{"alice 2"}},
+		{"NoOwner", taskQuery{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := list(tt.q); !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	bob := precondition{owner: "bob"}
+	if err := d.updateTask(context.TODO(), ids[0], &pb.Task{Done: true}, defaultUpdateMask(), bob); !errors.Is(err, errPermissionDenied) {
+		t.Errorf("expected %v updating another owner's task, got %v", errPermissionDenied, err)
+	}
+	if err := d.deleteTask(context.TODO(), ids[0], bob); !errors.Is(err, errPermissionDenied) {
+		t.Errorf("expected %v deleting another owner's task, got %v", errPermissionDenied, err)
+	}
+	if task, err := d.getTask(context.TODO(), ids[0]); err != nil || task.Done || task.Version != 1 {
+		t.Errorf("expected task to be left untouched, got %v, %v", task, err)
+	}
+	alice := precondition{owner: "alice"}
+	if err := d.updateTask(context.TODO(), ids[0], &pb.Task{Done: true}, &fieldmaskpb.FieldMask{Paths: []string{"done"}}, alice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.deleteTask(context.TODO(), ids[0], alice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func testDBDeleteTask(t *testing.T, d db) {
+	ids := addTasks(t, d, 3)
+	if err := d.deleteTask(context.TODO(), ids[1], precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.deleteTask(context.TODO(), ids[1], precondition{}); !errors.Is(err, errNotFound) {
+		t.Errorf("expected %v deleting task twice, got %v", errNotFound, err)
+	}
+
+	tasks := listTasks(t, d)
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(tasks))
+	}
+	if tasks[0].Id != ids[0] || tasks[1].Id != ids[2] {
+		t.Errorf("expected tasks %d and %d, got %v", ids[0], ids[2], tasks)
+	}
+}
+
+func testDBUniqueIDs(t *testing.T, d db) {
+	seen := make(map[uint64]bool)
+	var last uint64
+	check := func(ids ...uint64) {
+		t.Helper()
+		for _, id := range ids {
+			if seen[id] {
+				t.Fatalf("id %d was allocated twice", id)
+			}
+			if id <= last {
+				t.Fatalf("id %d allocated after %d", id, last)
+			}
+			seen[id] = true
+			last = id
+		}
+	}
+
+	ids := addTasks(t, d, 3)
+	check(ids...)
+	// deleting the first and the last task used to make the next
+	// id collide with the ones already allocated.
+	for _, id := range []uint64{ids[0], ids[2]} {
+		if err := d.deleteTask(context.TODO(), id, precondition{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	check(addTasks(t, d, 2)...)
+
+	// interleave adds and deletes until the database is empty
+	for i := 0; i < 10; i++ {
+		tasks := listTasks(t, d)
+		if err := d.deleteTask(context.TODO(), tasks[i%len(tasks)].Id, precondition{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		check(addTasks(t, d, 1)...)
+	}
+	for _, task := range listTasks(t, d) {
+		if err := d.deleteTask(context.TODO(), task.Id, precondition{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	check(addTasks(t, d, 1)...)
+}
+
+func testDBConcurrentUniqueIDs(t *testing.T, d db) {
+	const (
+		workers = 8
+		perWork = 20
+	)
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		all []uint64
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWork; i++ {
+				id, err := d.addTask(context.TODO(), "", "task", time.Now())
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				mu.Lock()
+				all = append(all, id)
+				mu.Unlock()
+				// delete every other task right away
+				if i%2 == 0 {
+					if err := d.deleteTask(context.TODO(), id, precondition{}); err != nil {
+						t.Errorf("unexpected error: %v", err)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[uint64]bool, len(all))
+	for _, id := range all {
+		if seen[id] {
+			t.Errorf("id %d was allocated twice", id)
+		}
+		seen[id] = true
+	}
+	if n, expected := len(listTasks(t, d)), workers*perWork/2; n != expected {
+		t.Errorf("expected %d tasks, got %d", expected, n)
+	}
+}
+
+// seedDB adds tasks to d, with their description, due date and done
+// fields, and returns their ids.
+func seedDB(t *testing.T, d db, tasks ...*pb.Task) []uint64 {
+	t.Helper()
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"done", "due_date"}}
+	ids := make([]uint64, 0, len(tasks))
+	for _, task := range tasks {
+		id, err := d.addTask(context.TODO(), "", task.Description, time.Now())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// addTask always sets a due date
+		if err := d.updateTask(context.TODO(), id, task, mask, precondition{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// filterIDs returns the ids of the tasks matched by filter.
+func filterIDs(t *testing.T, d db, filter taskFilter) []uint64 {
+	t.Helper()
+	return queryIDs(t, d, taskQuery{filter: filter})
+}
+
+// queryIDs returns the ids of the tasks selected by q.
+func queryIDs(t *testing.T, d db, q taskQuery) []uint64 {
+	t.Helper()
+	var ids []uint64
+	err := d.getTasks(context.TODO(), q, func(a any) error {
+		ids = append(ids, a.(*pb.Task).Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return ids
+}
+
+func testDBFilter(t *testing.T, d db) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	ids := seedDB(t, d,
+		&pb.Task{Description: "buy milk", DueDate: timestamppb.New(now.Add(-2 * time.Hour))}, // overdue
+		&pb.Task{Description: "buy bread", DueDate: timestamppb.New(now.Add(-time.Hour)), Done: true},
+		&pb.Task{Description: "write Report", DueDate: timestamppb.New(now.Add(time.Hour))},
+		&pb.Task{Description: "read report", DueDate: timestamppb.New(now.Add(2 * time.Hour)), Done: true},
+		&pb.Task{Description: "no due date"},
+	)
+
+	done, notDone := true, false
+	tests := []struct {
+		name     string
+		filter   taskFilter
+		expected []uint64
+	}{
+		{"All", taskFilter{}, ids},
+		{"Done", taskFilter{done: &done}, []uint64{ids[1], ids[3]}},
+		{"NotDone", taskFilter{done: &notDone}, []uint64{ids[0], ids[2], ids[4]}},
+		{"Overdue", taskFilter{overdueAt: now}, []uint64{ids[0]}},
+		{"DueAfter", taskFilter{dueAfter: now.Add(-time.Hour)}, []uint64{ids[1], ids[2], ids[3]}},
+		{"DueBefore", taskFilter{dueBefore: now.Add(time.Hour)}, []uint64{ids[0], ids[1]}},
+		{"DueRange", taskFilter{dueAfter: now.Add(-time.Hour), dueBefore: now.Add(2 * time.Hour)}, []uint64{ids[1], ids[2]}},
+		{"Description", taskFilter{description: "buy"}, []uint64{ids[0], ids[1]}},
+		{"DescriptionCaseSensitive", taskFilter{description: "Report"}, []uint64{ids[2]}},
+		{"Combined", taskFilter{done: &done, description: "re"}, []uint64{ids[1], ids[3]}},
+		{"NoMatch", taskFilter{overdueAt: now, description: "report"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterIDs(t, d, tt.filter); !slices.Equal(got, tt.expected) {
+				t.Errorf("expected tasks %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// testDBFilterBatches checks backends reading tasks in batches keep
+// going when a whole batch doesn't match.
+func testDBFilterBatches(t *testing.T, d db) {
+	ids := addTasks(t, d, 200)
+	for _, id := range []uint64{ids[3], ids[150], ids[199]} {
+		if err := d.updateTask(context.TODO(), id, &pb.Task{Done: true}, &fieldmaskpb.FieldMask{Paths: []string{"done"}}, precondition{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	done := true
+	expected := []uint64{ids[3], ids[150], ids[199]}
+	if got := filterIDs(t, d, taskFilter{done: &done}); !slices.Equal(got, expected) {
+		t.Errorf("expected tasks %v, got %v", expected, got)
+	}
+}
+
+func testDBPages(t *testing.T, d db) {
+	// more than a batch of the backends reading in batches
+	ids := addTasks(t, d, 150)
+
+	if got := queryIDs(t, d, taskQuery{limit: 100}); !slices.Equal(got, ids[:100]) {
+		t.Errorf("expected the first 100 tasks, got %v", got)
+	}
+	if got := queryIDs(t, d, taskQuery{after: &pb.Task{Id: ids[99]}, limit: 100}); !slices.Equal(got, ids[100:]) {
+		t.Errorf("expected the last 50 tasks, got %v", got)
+	}
+
+	// deleting the task a page ends with doesn't change the next page
+	if err := d.deleteTask(context.TODO(), ids[9], precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := queryIDs(t, d, taskQuery{after: &pb.Task{Id: ids[9]}, limit: 5}); !slices.Equal(got, ids[10:15]) {
+		t.Errorf("expected tasks %v, got %v", ids[10:15], got)
+	}
+
+	// tasks added are listed on the last page
+	added := addTasks(t, d, 1)
+	if got := queryIDs(t, d, taskQuery{after: &pb.Task{Id: ids[147]}}); !slices.Equal(got, append(ids[148:], added...)) {
+		t.Errorf("expected tasks %v, got %v", append(ids[148:], added...), got)
+	}
+
+	// the limit applies to the matching tasks
+	done := true
+	for _, id := range []uint64{ids[20], ids[80], ids[140]} {
+		if err := d.updateTask(context.TODO(), id, &pb.Task{Done: true}, &fieldmaskpb.FieldMask{Paths: []string{"done"}}, precondition{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	q := taskQuery{filter: taskFilter{done: &done}, after: &pb.Task{Id: ids[20]}, limit: 1}
+	if got := queryIDs(t, d, q); !slices.Equal(got, []uint64{ids[80]}) {
+		t.Errorf("expected task %d, got %v", ids[80], got)
+	}
+}
+
+func testDBOrder(t *testing.T, d db) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	ids := seedDB(t, d,
+		&pb.Task{Description: "b", DueDate: timestamppb.New(now.Add(time.Hour))},
+		&pb.Task{Description: "a", Done: true}, // no due date
+		&pb.Task{Description: "b", DueDate: timestamppb.New(now.Add(-time.Hour)), Done: true},
+		&pb.Task{Description: "C", DueDate: timestamppb.New(now.Add(time.Hour))},
+		&pb.Task{Description: "a", DueDate: timestamppb.New(now.Add(-time.Hour))},
+	)
+
+	tests := []struct {
+		name     string
+		order    taskOrder
+		expected []int // indexes in ids
+	}{
+		{"ID", taskOrder{}, []int{0, 1, 2, 3, 4}},
+		{"IDDesc", taskOrder{desc: true}, []int{4, 3, 2, 1, 0}},
+		{"DueDate", taskOrder{field: orderByDueDate}, []int{2, 4, 0, 3, 1}},
+		{"DueDateDesc", taskOrder{field: orderByDueDate, desc: true}, []int{1, 3, 0, 4, 2}},
+		{"Description", taskOrder{field: orderByDescription}, []int{3, 1, 4, 0, 2}},
+		{"DescriptionDesc", taskOrder{field: orderByDescription, desc: true}, []int{2, 0, 4, 1, 3}},
+		{"Done", taskOrder{field: orderByDone}, []int{0, 3, 4, 1, 2}},
+		{"DoneDesc", taskOrder{field: orderByDone, desc: true}, []int{2, 1, 4, 3, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expected []uint64
+			for _, i := range tt.expected {
+				expected = append(expected, ids[i])
+			}
+			if got := queryIDs(t, d, taskQuery{order: tt.order}); !slices.Equal(got, expected) {
+				t.Fatalf("expected tasks %v, got %v", expected, got)
+			}
+
+			// resuming after every task gives the same order
+			var (
+				got   []uint64
+				after *pb.Task
+			)
+			for {
+				var page []*pb.Task
+				err := d.getTasks(context.TODO(), taskQuery{order: tt.order, after: after, limit: 2}, func(a any) error {
+					page = append(page, a.(*pb.Task))
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(page) == 0 {
+					break
+				}
+				for _, task := range page {
+					got = append(got, task.Id)
+				}
+				after = page[len(page)-1]
+			}
+			if !slices.Equal(got, expected) {
+				t.Errorf("expected pages of tasks %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+// watchEvents watches the changes of d after since until the end of the
+// test.
+func watchEvents(t *testing.T, d db, since uint64) <-chan taskEvent {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan taskEvent, 100)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := d.watchTasks(ctx, since, func(e taskEvent) error {
+			events <- e
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("watchTasks: expected %v, got %v", context.Canceled, err)
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return events
+}
+
+// nextEvent returns the next event or fails the test if there is none
+// in time.
+func nextEvent(t *testing.T, events <-chan taskEvent) taskEvent {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return taskEvent{}
+	}
+}
+
+func testDBWatch(t *testing.T, d db) {
+	ids := addTasks(t, d, 2)
+	if err := d.updateTask(context.TODO(), ids[0], &pb.Task{Done: true}, &fieldmaskpb.FieldMask{Paths: []string{"done"}}, precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the first change of a new database has revision 1, the changes
+	// after it are sent first
+	events := watchEvents(t, d, 1)
+	e := nextEvent(t, events)
+	if e.revision != 2 || e.kind != eventCreated || e.task.Id != ids[1] || e.task.Description != "task 1" {
+		t.Errorf("expected task %d created at revision 2, got %v", ids[1], e)
+	}
+	e = nextEvent(t, events)
+	if e.revision != 3 || e.kind != eventUpdated || e.task.Id != ids[0] || !e.task.Done || e.task.Description != "task 0" {
+		t.Errorf("expected task %d updated at revision 3, got %v", ids[0], e)
+	}
+
+	// then the new ones
+	if err := d.deleteTask(context.TODO(), ids[1], precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e = nextEvent(t, events)
+	if e.revision != 4 || e.kind != eventDeleted || e.task.Id != ids[1] || e.task.Description != "task 1" {
+		t.Errorf("expected task %d deleted at revision 4, got %v", ids[1], e)
+	}
+	added := addTasks(t, d, 1)
+	e = nextEvent(t, events)
+	if e.revision != 5 || e.kind != eventCreated || e.task.Id != added[0] {
+		t.Errorf("expected task %d created at revision 5, got %v", added[0], e)
+	}
+
+	// failed writes are not recorded
+	if err := d.deleteTask(context.TODO(), ids[1], precondition{}); !errors.Is(err, errNotFound) {
+		t.Fatalf("expected %v, got %v", errNotFound, err)
+	}
+	// watching from now on skips the previous changes
+	events = watchEvents(t, d, 0)
+	// the watch might start after this update, which is why it's
+	// retried until seen
+	for i := 0; ; i++ {
+		if err := d.updateTask(context.TODO(), added[0], &pb.Task{Description: "updated"}, &fieldmaskpb.FieldMask{Paths: []string{"description"}}, precondition{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		select {
+		case e := <-events:
+			if e.revision <= 5 || e.kind != eventUpdated || e.task.Description != "updated" {
+				t.Errorf("expected an update after revision 5, got %v", e)
+			}
+			return
+		case <-time.After(100 * time.Millisecond):
+			if i == 50 {
+				t.Fatal("timed out waiting for an event")
+			}
+		}
+	}
+}
+
+func testDBWatchCancelled(t *testing.T, d db) {
+	addTasks(t, d, 2)
+
+	// f returning an error stops the watch
+	errStop := errors.New("stop")
+	if err := d.watchTasks(context.TODO(), 1, func(taskEvent) error { return errStop }); !errors.Is(err, errStop) {
+		t.Errorf("expected %v, got %v", errStop, err)
+	}
+
+	// and so does ctx, while waiting for changes
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := d.watchTasks(ctx, 0, func(e taskEvent) error {
+		t.Errorf("unexpected event: %v", e)
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+// testDBWatchAhead checks resuming after the last change fails, the
+// changes up to since would be missed.
+func testDBWatchAhead(t *testing.T, d db) {
+	addTasks(t, d, 2)
+
+	err := d.watchTasks(context.TODO(), 3, func(e taskEvent) error {
+		t.Errorf("unexpected event: %v", e)
+		return nil
+	})
+	if !errors.Is(err, errCompacted) {
+		t.Errorf("expected %v, got %v", errCompacted, err)
+	}
+
+	// the last change can be resumed after
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = d.watchTasks(ctx, 2, func(e taskEvent) error {
+		t.Errorf("unexpected event: %v", e)
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+// testDBWatchStoredTasks checks the changes hold the tasks as they are
+// read, with the precision the backend stores due dates with.
+func testDBWatchStoredTasks(t *testing.T, d db) {
+	addTasks(t, d, 1)
+	// nanoseconds aren't kept by every backend
+	dueDate := time.Date(2030, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	id, err := d.addTask(context.TODO(), "", "task", dueDate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	added, err := d.getTask(context.TODO(), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	update := &pb.Task{DueDate: timestamppb.New(dueDate.Add(time.Hour))}
+	if err := d.updateTask(context.TODO(), id, update, &fieldmaskpb.FieldMask{Paths: []string{"due_date"}}, precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updated, err := d.getTask(context.TODO(), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := watchEvents(t, d, 1)
+	for _, expected := range []*pb.Task{added, updated} {
+		if e := nextEvent(t, events); !proto.Equal(e.task, expected) {
+			t.Errorf("expected task %v, got %v", expected, e.task)
+		}
+	}
+}
+
+func testDBGetTasksReturnsCopies(t *testing.T, d db) {
+	addTasks(t, d, 1)
+	// ListTasks clears the fields filtered out by the mask, this
+	// should never be visible to other readers.
+	for _, task := range listTasks(t, d) {
+		task.Description = ""
+		task.DueDate = nil
+	}
+
+	task := listTasks(t, d)[0]
+	if task.Description != "task 0" || task.DueDate == nil {
+		t.Errorf("stored task was modified: %v", task)
+	}
+}
+
+func testDBConcurrentAccess(t *testing.T, d db) {
+	const (
+		workers = 8
+		perWork = 25
+	)
+	seeded := addTasks(t, d, workers)
+
+	// adding, updating and listing concurrently
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(3)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWork; i++ {
+				if _, err := d.addTask(context.TODO(), "", fmt.Sprintf("worker %d", w), time.Now()); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+		}(w)
+		go func(id uint64) {
+			defer wg.Done()
+			for i := 0; i < perWork; i++ {
+				update := fullUpdate(fmt.Sprintf("update %d", i), time.Now(), i%2 == 0)
+				if err := d.updateTask(context.TODO(), id, update, defaultUpdateMask(), precondition{}); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+		}(seeded[w])
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWork; i++ {
+				err := d.getTasks(context.TODO(), taskQuery{}, func(a any) error {
+					if task := a.(*pb.Task); task.Id == 0 {
+						return fmt.Errorf("got invalid task: %v", task)
+					}
+					return nil
+				})
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	expected := workers + workers*perWork
+	if n := len(listTasks(t, d)); n != expected {
+		t.Fatalf("expected %d tasks, got %d", expected, n)
+	}
+
+	// deleting and listing concurrently
+	for _, id := range seeded {
+		wg.Add(2)
+		go func(id uint64) {
+			defer wg.Done()
+			if err := d.deleteTask(context.TODO(), id, precondition{}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(id)
+		go func() {
+			defer wg.Done()
+			if err := d.getTasks(context.TODO(), taskQuery{}, func(any) error { return nil }); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	expected -= len(seeded)
+	if n := len(listTasks(t, d)); n != expected {
+		t.Errorf("expected %d tasks, got %d", expected, n)
+	}
+}
+
+func testDBCancelledContext(t *testing.T, d db) {
+	ids := addTasks(t, d, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := d.addTask(ctx, "", "test", time.Now()); !errors.Is(err, context.Canceled) {
+		t.Errorf("addTask: expected %v, got %v", context.Canceled, err)
+	}
+	err := d.getTasks(ctx, taskQuery{}, func(any) error {
+		t.Errorf("getTasks: f called with cancelled context")
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("getTasks: expected %v, got %v", context.Canceled, err)
+	}
+	if err := d.updateTask(ctx, ids[0], fullUpdate("test", time.Now(), true), defaultUpdateMask(), precondition{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("updateTask: expected %v, got %v", context.Canceled, err)
+	}
+	if err := d.deleteTask(ctx, ids[0], precondition{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("deleteTask: expected %v, got %v", context.Canceled, err)
+	}
+
+	// nothing was written
+	tasks := listTasks(t, d)
+	if len(tasks) != 1 || tasks[0].Description != "task 0" {
+		t.Errorf("expected the seeded task only, got %v", tasks)
+	}
+}
+
+func testDBCancelledWhileListing(t *testing.T, d db) {
+	addTasks(t, d, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	count := 0
+	err := d.getTasks(ctx, taskQuery{}, func(any) error {
+		count++
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if count != 1 {
+		t.Errorf("expected f to be called once, got %d", count)
+	}
+}
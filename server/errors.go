@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the db implementations. They are usually wrapped,
+// use errors.Is to check for them.
+var (
+	// errNotFound means the task doesn't exist.
+	errNotFound = errors.New("not found")
+	// errConflict means the operation conflicted with a concurrent one
+	// and can be retried.
+	errConflict = errors.New("conflict")
+	// errUnavailable means the storage couldn't be reached.
+	errUnavailable = errors.New("couldn't access the database")
+	// errVersionMismatch means the task doesn't have the version the
+	// operation expected, it was changed since it was read.
+	errVersionMismatch = errors.New("doesn't have the expected version")
+	// errPermissionDenied means the task belongs to another owner.
+	errPermissionDenied = errors.New("belongs to another owner")
+	// errCompacted means the changes requested were discarded, or
+	// resumed from a revision the change feed hasn't reached.
+	errCompacted = errors.New("changes after this revision are not available anymore")
+)
+
+// taskError is an error affecting a single task.
+type taskError struct {
+	id  uint64
+	err error
+}
+
+func (e *taskError) Error() string {
+	return fmt.Sprintf("task with id %d %v", e.id, e.err)
+}
+
+func (e *taskError) Unwrap() error {
+	return e.err
+}
+
+// taskNotFound returns the error for a task that doesn't exist.
+func taskNotFound(id uint64) error {
+	return &taskError{id: id, err: errNotFound}
+}
@@ -1,13 +1,20 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"sync/atomic"
 	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 type FakeDb struct {
 	d    *inMemoryDB
 	opts testOptions
+	// cancelled counts the calls given up because their context was
+	// done while waiting for opts.delay.
+	cancelled uint32
 }
 
 func NewFakeDb(opt ...TestOption) *FakeDb {
@@ -17,52 +24,103 @@ func NewFakeDb(opt ...TestOption) *FakeDb {
 	}
 
 	return &FakeDb{
-		d:    &inMemoryDB{},
+		d:    New(),
 		opts: opts,
 	}
 }
 
 func (db *FakeDb) Reset() {
 	db.opts = defaultTestOptions
-	db.d = &inMemoryDB{}
+	db.d = New()
+	atomic.StoreUint32(&db.cancelled, 0)
+}
+
+// Cancelled returns the number of calls aborted because of their context.
+func (db *FakeDb) Cancelled() int {
+	return int(atomic.LoadUint32(&db.cancelled))
+}
+
+// wait simulates a slow database. It returns early with ctx.Err()
+// if ctx is done before opts.delay.
+func (db *FakeDb) wait(ctx context.Context) error {
+	if db.opts.delay == 0 {
+		return nil
+	}
+	select {
+	case <-time.After(db.opts.delay):
+		return nil
+	case <-ctx.Done():
+		atomic.AddUint32(&db.cancelled, 1)
+		return ctx.Err()
+	}
 }
 
-func (db *FakeDb) addTask(description string, dueDate time.Time) (uint64, error) {
+func (db *FakeDb) addTask(ctx context.Context, owner, description string, dueDate time.Time) (uint64, error) {
 	if !db.opts.isAvailable {
-		return 0, fmt.Errorf(
-			"couldn't access the database",
-		)
+		return 0, errUnavailable
 	}
-	return db.d.addTask(description, dueDate)
+	if err := db.wait(ctx); err != nil {
+		return 0, err
+	}
+	return db.d.addTask(ctx, owner, description, dueDate)
 }
 
-func (db *FakeDb) getTasks(f func(interface{}) error) error {
+func (db *FakeDb) addTasks(ctx context.Context, tasks []newTask) ([]uint64, error) {
 	if !db.opts.isAvailable {
-		return fmt.Errorf(
-			// the error message is different only because we
-			// do not handle the error, we directly return it
-			// in ListTasks. For other endpoints, we generally
-			// prepend with "unexpected error: ".
-			"unexpected error: couldn't access the database",
-		)
-	}
-	return db.d.getTasks(f)
+		return nil, errUnavailable
+	}
+	if err := db.wait(ctx); err != nil {
+		return nil, err
+	}
+	return db.d.addTasks(ctx, tasks)
 }
 
-func (db *FakeDb) updateTask(id uint64, description string, dueDate time.Time, done bool) error {
+func (db *FakeDb) getTask(ctx context.Context, id uint64) (*pb.Task, error) {
 	if !db.opts.isAvailable {
-		return fmt.Errorf(
-			"couldn't access the database",
-		)
+		return nil, errUnavailable
+	}
+	if err := db.wait(ctx); err != nil {
+		return nil, err
 	}
-	return db.d.updateTask(id, description, dueDate, done)
+	return db.d.getTask(ctx, id)
 }
 
-func (db *FakeDb) deleteTask(id uint64) error {
+func (db *FakeDb) getTasks(ctx context.Context, q taskQuery, f func(interface{}) error) error {
 	if !db.opts.isAvailable {
-		return fmt.Errorf(
-			"couldn't access the database",
-		)
+		return errUnavailable
+	}
+	if err := db.wait(ctx); err != nil {
+		return err
+	}
+	return db.d.getTasks(ctx, q, f)
+}
+
+func (db *FakeDb) updateTask(ctx context.Context, id uint64, update *pb.Task, mask *fieldmaskpb.FieldMask, pre precondition) error {
+	if !db.opts.isAvailable {
+		return errUnavailable
+	}
+	if err := db.wait(ctx); err != nil {
+		return err
+	}
+	return db.d.updateTask(ctx, id, update, mask, pre)
+}
+
+func (db *FakeDb) deleteTask(ctx context.Context, id uint64, pre precondition) error {
+	if !db.opts.isAvailable {
+		return errUnavailable
+	}
+	if err := db.wait(ctx); err != nil {
+		return err
+	}
+	return db.d.deleteTask(ctx, id, pre)
+}
+
+func (db *FakeDb) watchTasks(ctx context.Context, since uint64, f func(taskEvent) error) error {
+	if !db.opts.isAvailable {
+		return errUnavailable
+	}
+	if err := db.wait(ctx); err != nil {
+		return err
 	}
-	return db.d.deleteTask(id)
+	return db.d.watchTasks(ctx, since, f)
 }
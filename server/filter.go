@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+)
+
+// taskFilter is the storage side version of pb.TaskFilter. The zero
+// value matches every task.
+type taskFilter struct {
+	done *bool
+	// overdueAt, if not zero, only keeps the tasks overdue at that
+	// time (see isOverdue).
+	overdueAt time.Time
+	// dueAfter (inclusive) and dueBefore (exclusive), if not zero,
+	// only keep the tasks with a due date in that range.
+	dueAfter, dueBefore time.Time
+	description         string
+}
+
+// newTaskFilter converts f, with now being the time overdue tasks are
+// checked against.
+func newTaskFilter(f *pb.TaskFilter, now time.Time) (taskFilter, error) {
+	var tf taskFilter
+	if f == nil {
+		return tf, nil
+	}
+	if f.Done != nil {
+		done := *f.Done
+		tf.done = &done
+	}
+	if f.OverdueOnly {
+		tf.overdueAt = now
+	}
+	if f.DueAfter != nil {
+		if err := f.DueAfter.CheckValid(); err != nil {
+			return tf, err
+		}
+		tf.dueAfter = f.DueAfter.AsTime()
+	}
+	if f.DueBefore != nil {
+		if err := f.DueBefore.CheckValid(); err != nil {
+			return tf, err
+		}
+		tf.dueBefore = f.DueBefore.AsTime()
+	}
+	if !tf.dueAfter.IsZero() && !tf.dueBefore.IsZero() && !tf.dueAfter.Before(tf.dueBefore) {
+		return tf, errors.New("due_after should be before due_before")
+	}
+	tf.description = f.DescriptionContains
+	return tf, nil
+}
+
+// match reports whether task is kept by f. Backends able to filter
+// natively should do so with the same semantics.
+func (f taskFilter) match(task *pb.Task) bool {
+	if f.done != nil && task.Done != *f.done {
+		return false
+	}
+	hasDate := !f.overdueAt.IsZero() || !f.dueAfter.IsZero() || !f.dueBefore.IsZero()
+	if hasDate {
+		if task.DueDate == nil {
+			return false
+		}
+		due := task.DueDate.AsTime()
+		if !f.overdueAt.IsZero() && !isOverdue(task, f.overdueAt) {
+			return false
+		}
+		if !f.dueAfter.IsZero() && due.Before(f.dueAfter) {
+			return false
+		}
+		if !f.dueBefore.IsZero() && !due.Before(f.dueBefore) {
+			return false
+		}
+	}
+	return strings.Contains(task.Description, f.description)
+}
+
+// isOverdue reports whether task is overdue at now: it is not done and
+// its due date is past. Tasks without due date are never overdue.
+func isOverdue(task *pb.Task, now time.Time) bool {
+	return !task.Done && task.DueDate != nil && task.DueDate.AsTime().Before(now)
+}
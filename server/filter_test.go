@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestNewTaskFilter(t *testing.T) {
+	now := time.Now()
+	f, err := newTaskFilter(&pb.TaskFilter{
+		Done:                proto.Bool(false),
+		OverdueOnly:         true,
+		DueAfter:            timestamppb.New(now.Add(-time.Hour)),
+		DescriptionContains: "milk",
+	}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.done == nil || *f.done || !f.overdueAt.Equal(now) || !f.dueAfter.Equal(now.Add(-time.Hour)) ||
+		!f.dueBefore.IsZero() || f.description != "milk" {
+		t.Errorf("unexpected filter: %+v", f)
+	}
+
+	if f, err := newTaskFilter(nil, now); err != nil || f != (taskFilter{}) {
+		t.Errorf("expected an empty filter, got %+v, %v", f, err)
+	}
+}
+
+func TestNewTaskFilterInvalid(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		filter *pb.TaskFilter
+	}{
+		{"EmptyRange", &pb.TaskFilter{DueAfter: timestamppb.New(now), DueBefore: timestamppb.New(now)}},
+		{"ReversedRange", &pb.TaskFilter{DueAfter: timestamppb.New(now), DueBefore: timestamppb.New(now.Add(-time.Hour))}},
+		{"InvalidTimestamp", &pb.TaskFilter{DueBefore: &timestamppb.Timestamp{Nanos: -1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newTaskFilter(tt.filter, now); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestIsOverdue(t *testing.T) {
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		task     *pb.Task
+		expected bool
+	}{
+		{"Past", &pb.Task{DueDate: timestamppb.New(now.Add(-time.Second))}, true},
+		{"Future", &pb.Task{DueDate: timestamppb.New(now.Add(time.Second))}, false},
+		{"Now", &pb.Task{DueDate: timestamppb.New(now)}, false},
+		{"PastDone", &pb.Task{Done: true, DueDate: timestamppb.New(now.Add(-time.Second))}, false},
+		{"NoDueDate", &pb.Task{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOverdue(tt.task, now); got != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
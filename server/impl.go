@@ -2,99 +2,219 @@ package main
 
 import (
 	"context"
+	"errors"
 	"io"
-	"log"
 	"time"
 
 	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
-	"golang.org/x/exp/slices"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
-	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
-func Filter(msg proto.Message, mask *fieldmaskpb.FieldMask) {
-	if mask == nil || len(mask.Paths) == 0 {
-		return
-	}
-	rft := msg.ProtoReflect()
-	rft.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
-		if !slices.Contains(mask.Paths, string(fd.Name())) {
-			rft.Clear(fd)
-		}
-		return true
-	})
-}
-
 func (s *server) AddTask(ctx context.Context, in *pb.AddTaskRequest) (*pb.AddTaskResponse, error) {
 	if err := in.Validate(); err != nil {
 		return nil, err
 	}
-	log.Println("got duedate:", in.DueDate.AsTime())
-	id, err := s.d.addTask(in.Description, in.DueDate.AsTime())
+	s.opts.logger.DebugContext(ctx, "adding task", "due_date", in.DueDate.AsTime())
+	id, err := s.d.addTask(ctx, ownerFromContext(ctx), in.Description, in.DueDate.AsTime())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "unexpected error: %s", err.Error())
+		return nil, toStatus(err)
 	}
 	return &pb.AddTaskResponse{Id: id}, nil
 }
 
+func (s *server) AddTasks(ctx context.Context, req *pb.AddTasksRequest) (*pb.AddTasksResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	owner := ownerFromContext(ctx)
+	tasks := make([]newTask, len(req.Tasks))
+	for i, t := range req.Tasks {
+		tasks[i] = newTask{owner: owner, description: t.Description, dueDate: t.DueDate.AsTime()}
+	}
+	s.opts.logger.DebugContext(ctx, "adding tasks", "count", len(tasks))
+	ids, err := s.d.addTasks(ctx, tasks)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AddTasksResponse{Ids: ids}, nil
+}
+
+func (s *server) GetTask(ctx context.Context, req *pb.GetTaskRequest) (*pb.GetTaskResponse, error) {
+	mask, err := compileMask(taskDescriptor, req.Mask)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid mask: %v", err)
+	}
+	task, err := s.d.getTask(ctx, req.Id)
+	if err == nil {
+		err = precondition{owner: ownerFromContext(ctx)}.check(task)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	// before the mask clears the fields it depends on
+	overdue := isOverdue(task, s.opts.now())
+	mask.filter(task.ProtoReflect())
+	return &pb.GetTaskResponse{
+		Task:    task,
+		Overdue: overdue,
+	}, nil
+}
+
 func (s *server) ListTasks(req *pb.ListTasksRequest, stream pb.TodoService_ListTasksServer) error {
 	ctx := stream.Context()
-	return s.d.getTasks(func(a any) error {
-		select {
-		case <-ctx.Done():
-			switch ctx.Err() {
-			case context.Canceled:
-				log.Printf("request canceled: %s", ctx.Err())
-			case context.DeadlineExceeded:
-				log.Printf("req deadline exeeded: %s", ctx.Err())
-			default:
+	mask, err := compileMask(taskDescriptor, req.Mask)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid mask: %v", err)
+	}
+	now := s.opts.now()
+	filter, err := newTaskFilter(req.Filter, now)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid filter: %v", err)
+	}
+	if req.PageSize < 0 {
+		return status.Error(codes.InvalidArgument, "invalid page_size: should be positive")
+	}
+	order, err := newTaskOrder(req.OrderBy)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid order_by: %v", err)
+	}
+	token, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid page_token: %v", err)
+	}
+	if req.PageToken != "" && !token.resumes(order) {
+		return status.Error(codes.InvalidArgument, "invalid page_token: listing in another order_by")
+	}
+
+	pageSize := int(req.PageSize)
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	q := taskQuery{owner: ownerFromContext(ctx), filter: filter, order: order}
+	if req.PageToken != "" {
+		q.after = token.cursor()
+	}
+	if pageSize > 0 {
+		// one more task tells if the last one of the page needs a
+		// next_page_token
+		q.limit = pageSize + 1
+	}
+
+	// every task is sent once the next one is read, with a token to
+	// resume after it, the last one is sent without.
+	pacer := rate.NewLimiter(s.opts.listRate, s.opts.listBurst)
+	var (
+		pending      *pb.ListTasksResponse
+		pendingToken pageToken
+		sent         int
+	)
+	err = s.d.getTasks(ctx, q, func(a any) error {
+		if pending != nil {
+			pending.NextPageToken = pendingToken.encode()
+			if err := stream.Send(pending); err != nil {
+				return err
 			}
-			return ctx.Err()
-		case <-time.After(200 * time.Millisecond):
+			pending = nil
+			sent++
+		}
+		if sent == pageSize && pageSize > 0 {
+			return nil
+		}
+
+		if err := s.pace(ctx, pacer); err != nil {
+			return err
 		}
-		// TODO: replace following case by default: on production API
 		task := a.(*pb.Task)
-		Filter(task, req.Mask)
-		log.Println("TASK: ", task)
-		log.Println("due date:", task.DueDate != nil)
-		log.Println("!done:", !task.Done)
-		log.Println("before:", task.DueDate.AsTime().Before(time.Now().UTC()))
-		overdue := task.DueDate != nil && !task.Done && task.DueDate.AsTime().After(time.Now().UTC())
-		log.Println("OVERDUE:", overdue)
-		err := stream.Send(&pb.ListTasksResponse{
+		pendingToken = newPageToken(order, task)
+		// before the mask clears the fields it depends on
+		overdue := isOverdue(task, now)
+		s.opts.logger.DebugContext(ctx, "listing task", "id", task.Id, "overdue", overdue)
+		mask.filter(task.ProtoReflect())
+		pending = &pb.ListTasksResponse{
 			Task:    task,
 			Overdue: overdue,
-		})
-		return err
+		}
+		return nil
 	})
+	if err == nil && pending != nil {
+		err = stream.Send(pending)
+	}
+	return toStatus(err)
+}
+
+// pace waits for pacer to allow sending one more task, on the clock of
+// the server. It gives up right away if the deadline of ctx is before
+// the task can be sent.
+func (s *server) pace(ctx context.Context, pacer *rate.Limiter) error {
+	now := s.opts.now()
+	r := pacer.ReserveN(now, 1)
+	delay := r.DelayFrom(now)
+	if delay == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		r.CancelAt(now)
+		return context.DeadlineExceeded
+	}
+	return s.opts.sleep(ctx, delay)
 }
 
 func (s *server) UpdateTasks(stream pb.TodoService_UpdateTasksServer) error {
+	ctx := stream.Context()
+	owner := ownerFromContext(ctx)
 	totalLength := 0
+	res := &pb.UpdateTasksResponse{}
+	fail := func(id uint64, st *status.Status) {
+		res.Failed++
+		res.Failures = append(res.Failures, &pb.UpdateTasksResponse_Failure{
+			Id:     id,
+			Reason: st.Message(),
+			Code:   int32(st.Code()),
+		})
+	}
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
-			log.Println("TOTAL:", totalLength)
-			return stream.SendAndClose(&pb.UpdateTasksResponse{})
+			s.opts.logger.InfoContext(ctx, "updated tasks",
+				"applied", res.Applied, "failed", res.Failed, "bytes_received", totalLength)
+			return stream.SendAndClose(res)
 		}
 		if err != nil {
 			return err
 		}
 		out, _ := proto.Marshal(req)
 		totalLength += len(out)
-		s.d.updateTask(
-			req.Id,
-			req.Description,
-			req.DueDate.AsTime(),
-			req.Done,
-		)
+		mask := req.UpdateMask
+		if len(mask.GetPaths()) == 0 {
+			mask = defaultUpdateMask()
+		}
+		if err := validateUpdateMask(mask); err != nil {
+			fail(req.Id, status.Newf(codes.InvalidArgument, "invalid update_mask: %v", err))
+			continue
+		}
+		update := &pb.Task{
+			Description: req.Description,
+			Done:        req.Done,
+			DueDate:     req.DueDate,
+		}
+		pre := precondition{owner: owner, version: req.ExpectedVersion}
+		if err := s.d.updateTask(ctx, req.Id, update, mask, pre); err != nil {
+			if ctx.Err() != nil {
+				return toStatus(ctx.Err())
+			}
+			fail(req.Id, status.Convert(toStatus(err)))
+			continue
+		}
+		res.Applied++
 	}
 }
 
 func (s *server) DeleteTasks(stream pb.TodoService_DeleteTasksServer) error {
+	ctx := stream.Context()
+	owner := ownerFromContext(ctx)
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
@@ -103,7 +223,38 @@ func (s *server) DeleteTasks(stream pb.TodoService_DeleteTasksServer) error {
 		if err != nil {
 			return err
 		}
-		s.d.deleteTask(req.Id)
-		stream.Send(&pb.DeleteTasksResponse{})
+		res := &pb.DeleteTasksResponse{
+			Id:     req.Id,
+			Status: pb.DeleteTasksResponse_STATUS_DELETED,
+		}
+		err = s.d.deleteTask(ctx, req.Id, precondition{owner: owner, version: req.ExpectedVersion})
+		switch {
+		case err == nil:
+		case errors.Is(err, errNotFound):
+			res.Status = pb.DeleteTasksResponse_STATUS_NOT_FOUND
+		case errors.Is(err, errVersionMismatch):
+			res.Status = pb.DeleteTasksResponse_STATUS_VERSION_MISMATCH
+		case errors.Is(err, errPermissionDenied):
+			res.Status = pb.DeleteTasksResponse_STATUS_PERMISSION_DENIED
+		case ctx.Err() != nil:
+			return toStatus(ctx.Err())
+		default:
+			res.Status = pb.DeleteTasksResponse_STATUS_ERROR
+			res.Error = status.Convert(toStatus(err)).Message()
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
 	}
 }
+
+func (s *server) WatchTasks(req *pb.WatchTasksRequest, stream pb.TodoService_WatchTasksServer) error {
+	owner := ownerFromContext(stream.Context())
+	err := s.d.watchTasks(stream.Context(), req.SinceRevision, func(e taskEvent) error {
+		if e.task.Owner != owner {
+			return nil
+		}
+		return stream.Send(e.toResponse())
+	})
+	return toStatus(err)
+}
@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
+	"net"
 	"testing"
+	"time"
 
 	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 func newClient(t *testing.T) (*grpc.ClientConn, pb.TodoServiceClient) {
@@ -21,6 +25,35 @@ func newClient(t *testing.T) (*grpc.ClientConn, pb.TodoServiceClient) {
 	return conn, pb.NewTodoServiceClient(conn)
 }
 
+// startServer serves d, with opts, until the end of the test and
+// returns a client of that server.
+func startServer(tb testing.TB, d db, opts ...ServerOption) pb.TodoServiceClient {
+	tb.Helper()
+	return startGrpcServer(tb, nil, d, opts...)
+}
+
+// startGrpcServer is startServer with the gRPC server created with
+// grpcOpts, for its interceptors.
+func startGrpcServer(tb testing.TB, grpcOpts []grpc.ServerOption, d db, opts ...ServerOption) pb.TodoServiceClient {
+	tb.Helper()
+	lis := bufconn.Listen(bufSize)
+	s := grpc.NewServer(grpcOpts...)
+	pb.RegisterTodoServiceServer(s, newServer(d, opts...))
+	go s.Serve(lis)
+	tb.Cleanup(s.Stop)
+
+	dialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(context.Background(), "bufnet", grpc.WithContextDialer(dialer), creds)
+	if err != nil {
+		tb.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	tb.Cleanup(func() { conn.Close() })
+	return pb.NewTodoServiceClient(conn)
+}
+
 func errorIs(err error, code codes.Code, msg string) bool {
 	if err != nil {
 		if s, ok := status.FromError(err); ok {
@@ -31,3 +64,36 @@ func errorIs(err error, code codes.Code, msg string) bool {
 	}
 	return false
 }
+
+// seedTasks replaces the content of fakeDB with the given tasks.
+// The ids are allocated by the database, in order, starting at 1, only
+// the description, due date and done fields of tasks are used.
+func seedTasks(t *testing.T, tasks ...*pb.Task) {
+	t.Helper()
+	fakeDB.d = New()
+	for _, task := range tasks {
+		id, err := fakeDB.d.addTask(context.TODO(), "", task.Description, task.DueDate.AsTime())
+		if err != nil {
+			t.Fatalf("failed seeding tasks: %v", err)
+		}
+		if task.Done {
+			mask := &fieldmaskpb.FieldMask{Paths: []string{"done"}}
+			if err := fakeDB.d.updateTask(context.TODO(), id, task, mask, precondition{}); err != nil {
+				t.Fatalf("failed seeding tasks: %v", err)
+			}
+		}
+	}
+}
+
+// waitCancelled waits for fakeDB to have aborted n calls because of
+// their context.
+func waitCancelled(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for fakeDB.Cancelled() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d cancelled database calls, got %d", n, fakeDB.Cancelled())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,60 +1,242 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"sync"
 	"time"
 
 	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// inMemoryDB is a db safe for concurrent use.
+//
+// Stored tasks are never mutated in place: writers replace the task
+// pointer under the write lock, so readers can take a cheap snapshot of
+// the pointers and iterate it without holding any lock.
 type inMemoryDB struct {
-	tasks []*pb.Task
+	mu     sync.RWMutex
+	tasks  map[uint64]*pb.Task
+	ids    []uint64 // sorted, ids are allocated in increasing order
+	lastID uint64   // last allocated id, ids are never reused
+	// events are at least the last eventRetention changes, their
+	// revisions are contiguous and end with revision.
+	events   []taskEvent
+	revision uint64
+	changes  notifier
 }
 
 func New() *inMemoryDB {
-	return &inMemoryDB{}
+	return &inMemoryDB{
+		tasks: make(map[uint64]*pb.Task),
+	}
+}
+
+func (d *inMemoryDB) addTask(ctx context.Context, owner, description string, dueDate time.Time) (uint64, error) {
+	ids, err := d.addTasks(ctx, []newTask{{owner, description, dueDate}})
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
 }
 
-func (d *inMemoryDB) addTask(description string, dueDate time.Time) (uint64, error) {
-	nextID := uint64(len(d.tasks) + 1)
-	task := &pb.Task{
-		Id:          nextID,
-		Description: description,
-		DueDate:     timestamppb.New(dueDate),
+func (d *inMemoryDB) addTasks(ctx context.Context, tasks []newTask) ([]uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ids := make([]uint64, 0, len(tasks))
+	for _, t := range tasks {
+		d.lastID++
+		nextID := d.lastID
+		d.tasks[nextID] = &pb.Task{
+			Id:          nextID,
+			Description: t.description,
+			DueDate:     timestamppb.New(t.dueDate),
+			Version:     1,
+			Owner:       t.owner,
+		}
+		d.ids = append(d.ids, nextID)
+		d.record(eventCreated, d.tasks[nextID])
+		ids = append(ids, nextID)
 	}
-	d.tasks = append(d.tasks, task)
-	return nextID, nil
+	return ids, nil
 }
 
-func (d *inMemoryDB) getTasks(f func(any) error) error {
-	for _, task := range d.tasks {
-		if err := f(task); err != nil {
+// record appends a change of task to the change feed. The write lock
+// must be held.
+func (d *inMemoryDB) record(kind eventKind, task *pb.Task) {
+	d.revision++
+	d.events = append(d.events, taskEvent{
+		revision: d.revision,
+		kind:     kind,
+		task:     task,
+	})
+	// trimmed once in a while rather than on every change
+	if len(d.events) >= 2*eventRetention {
+		d.events = slices.Clone(d.events[len(d.events)-eventRetention:])
+	}
+	d.changes.notify()
+}
+
+// getTask returns a copy of the task, stored tasks are immutable.
+func (d *inMemoryDB) getTask(ctx context.Context, id uint64) (*pb.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	task, ok := d.tasks[id]
+	d.mu.RUnlock()
+	if !ok {
+		return nil, taskNotFound(id)
+	}
+	return proto.Clone(task).(*pb.Task), nil
+}
+
+// getTasks calls f with a copy of every task selected by q, as they
+// were when getTasks was called. Writes happening during the iteration
+// are not visible to f.
+func (d *inMemoryDB) getTasks(ctx context.Context, q taskQuery, f func(any) error) error {
+	for _, task := range d.selectTasks(q) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := f(proto.Clone(task)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (d *inMemoryDB) updateTask(id uint64, description string, dueDate time.Time, done bool) error {
-	for i, task := range d.tasks {
-		if task.Id == id {
-			t := d.tasks[i]
-			t.Description = description
-			t.DueDate = timestamppb.New(dueDate)
-			t.Done = done
-			return nil
+// selectTasks returns the tasks selected by q. Tasks are sorted by id,
+// other orders require sorting every matching task.
+func (d *inMemoryDB) selectTasks(q taskQuery) []*pb.Task {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	ids := d.ids
+	if q.order.field != orderByID {
+		var tasks []*pb.Task
+		for _, id := range ids {
+			if task := d.tasks[id]; q.match(task) && (q.after == nil || q.order.compare(task, q.after) > 0) {
+				tasks = append(tasks, task)
+			}
+		}
+		slices.SortFunc(tasks, q.order.compare)
+		if q.limit > 0 && len(tasks) > q.limit {
+			tasks = tasks[:q.limit]
 		}
+		return tasks
 	}
-	return fmt.Errorf("task with id %d not found", id)
-}
 
-func (d *inMemoryDB) deleteTask(id uint64) error {
-	for i, task := range d.tasks {
-		if task.Id == id {
-			d.tasks = append(d.tasks[:i], d.tasks[i+1:]...)
-			return nil
+	if q.after != nil {
+		i, found := slices.BinarySearch(ids, q.after.Id)
+		if q.order.desc {
+			ids = ids[:i]
+		} else {
+			if found {
+				i++
+			}
+			ids = ids[i:]
 		}
 	}
-	return fmt.Errorf("task with id %d not found", id)
+	var tasks []*pb.Task
+	for i := range ids {
+		if q.limit > 0 && len(tasks) == q.limit {
+			break
+		}
+		id := ids[i]
+		if q.order.desc {
+			id = ids[len(ids)-1-i]
+		}
+		if task := d.tasks[id]; q.match(task) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+func (d *inMemoryDB) updateTask(ctx context.Context, id uint64, update *pb.Task, mask *fieldmaskpb.FieldMask, pre precondition) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	task, ok := d.tasks[id]
+	if !ok {
+		return taskNotFound(id)
+	}
+	if err := pre.check(task); err != nil {
+		return err
+	}
+	t := proto.Clone(task).(*pb.Task)
+	applyUpdate(t, update, mask)
+	d.tasks[id] = t
+	d.record(eventUpdated, t)
+	return nil
+}
+
+func (d *inMemoryDB) deleteTask(ctx context.Context, id uint64, pre precondition) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	task, ok := d.tasks[id]
+	if !ok {
+		return taskNotFound(id)
+	}
+	if err := pre.check(task); err != nil {
+		return err
+	}
+	delete(d.tasks, id)
+	d.record(eventDeleted, task)
+	if i, ok := slices.BinarySearch(d.ids, id); ok {
+		d.ids = slices.Delete(d.ids, i, i+1)
+	}
+	return nil
+}
+
+func (d *inMemoryDB) watchTasks(ctx context.Context, since uint64, f func(taskEvent) error) error {
+	return watchChanges(ctx, d, &d.changes, 0, since, f)
+}
+
+func (d *inMemoryDB) lastRevision(ctx context.Context) (uint64, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.revision, ctx.Err()
+}
+
+// eventsAfter returns copies of the tasks, stored ones are immutable
+// but f might modify them.
+func (d *inMemoryDB) eventsAfter(ctx context.Context, rev uint64) ([]taskEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if rev >= d.revision {
+		return nil, nil
+	}
+	events := d.events
+	if first := d.revision - uint64(len(d.events)) + 1; rev >= first {
+		events = events[rev+1-first:]
+	}
+	if len(events) > eventBatchSize {
+		events = events[:eventBatchSize]
+	}
+	copies := make([]taskEvent, len(events))
+	for i, e := range events {
+		e.task = proto.Clone(e.task).(*pb.Task)
+		copies[i] = e
+	}
+	return copies, nil
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+)
+
+func TestInMemoryDB(t *testing.T) {
+	testDB(t, func(*testing.T) db { return New() })
+	t.Run("GetTasksSnapshot", testInMemoryGetTasksSnapshot)
+	t.Run("WatchCompacted", testInMemoryWatchCompacted)
+}
+
+func testInMemoryGetTasksSnapshot(t *testing.T) {
+	d := New()
+	ids := addTasks(t, d, 3)
+
+	var seen []*pb.Task
+	err := d.getTasks(context.TODO(), taskQuery{}, func(a any) error {
+		if len(seen) == 0 {
+			if _, err := d.addTask(context.TODO(), "", "added while listing", time.Now()); err != nil {
+				return err
+			}
+			if err := d.updateTask(context.TODO(), ids[1], fullUpdate("updated while listing", time.Now(), true), defaultUpdateMask(), precondition{}); err != nil {
+				return err
+			}
+			if err := d.deleteTask(context.TODO(), ids[2], precondition{}); err != nil {
+				return err
+			}
+		}
+		seen = append(seen, a.(*pb.Task))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(seen) != len(ids) {
+		t.Fatalf("expected %d tasks, got %d", len(ids), len(seen))
+	}
+	for i, task := range seen {
+		if task.Id != ids[i] {
+			t.Errorf("expected task %d, got %d", ids[i], task.Id)
+		}
+	}
+	if seen[1].Description != "task 1" || seen[1].Done {
+		t.Errorf("update visible in snapshot: %v", seen[1])
+	}
+}
+
+func testInMemoryWatchCompacted(t *testing.T) {
+	d := New()
+	addTasks(t, d, 2*eventRetention)
+
+	err := d.watchTasks(context.TODO(), 1, func(e taskEvent) error {
+		t.Errorf("unexpected event: %v", e)
+		return nil
+	})
+	if !errors.Is(err, errCompacted) {
+		t.Errorf("expected %v, got %v", errCompacted, err)
+	}
+
+	// the last eventRetention changes are kept
+	since := uint64(eventRetention)
+	var got uint64
+	errStop := errors.New("stop")
+	err = d.watchTasks(context.TODO(), since, func(e taskEvent) error {
+		got = e.revision
+		return errStop
+	})
+	if !errors.Is(err, errStop) || got != since+1 {
+		t.Errorf("expected revision %d, got %d, %v", since+1, got, err)
+	}
+}
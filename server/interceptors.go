@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -12,29 +15,76 @@ import (
 )
 
 const (
-	authTokenKey   string = "auth_token"
-	authTokenValue string = "authd"
-	grpcService           = 5
-	grpcMethod            = 7
+	authTokenKey     string = "auth_token"
+	authorizationKey string = "authorization"
+	grpcService             = 5
+	grpcMethod              = 7
 )
 
-func validateAuthToken(ctx context.Context) (context.Context, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, status.Errorf(codes.Unauthenticated, "incorrect auth_token")
-	}
-	if t, ok := md[authTokenKey]; ok {
-		switch {
-		case len(t) != 1:
-			fmt.Printf("token: %v\n", t)
-			return nil, status.Errorf(codes.InvalidArgument, "auth_token should contain only 1 value")
-		case t[0] != authTokenValue:
+// authFunc returns the function of the auth interceptors, checking the
+// calls with a.
+func authFunc(a Authenticator) auth.AuthFunc {
+	return func(ctx context.Context) (context.Context, error) {
+		return validateAuthToken(ctx, a)
+	}
+}
+
+// validateAuthToken checks the token of the call with a, and returns
+// ctx carrying the authenticated principal (see principalFromContext).
+// The token is sent in the authorization metadata with the Bearer
+// scheme or, if it isn't set, in the auth_token metadata. No token is
+// needed when a is a certAuthenticator.
+func validateAuthToken(ctx context.Context, a Authenticator) (context.Context, error) {
+	var token string
+	if _, ok := a.(certAuthenticator); !ok {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
 			return nil, status.Errorf(codes.Unauthenticated, "incorrect auth_token")
 		}
-	} else {
-		return nil, status.Errorf(codes.Unauthenticated, "failed to get auth token")
+		var err error
+		if token, err = tokenFromMD(md); err != nil {
+			return nil, err
+		}
+	}
+	p, err := a.Authenticate(ctx, token)
+	if errors.Is(err, errInvalidToken) {
+		return nil, status.Errorf(codes.Unauthenticated, "incorrect auth_token: %v", err)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if p == nil {
+		return nil, status.Errorf(codes.Internal, "authenticator returned no principal")
+	}
+	// the tasks without owner are the ones added before authentication
+	if p.Subject == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "incorrect auth_token: no subject")
+	}
+	if p.Peer == "" {
+		p.Peer, _ = clientIdentity(ctx)
+	}
+	return withPrincipal(ctx, p), nil
+}
+
+func tokenFromMD(md metadata.MD) (string, error) {
+	if v, ok := md[authorizationKey]; ok {
+		if len(v) != 1 {
+			return "", status.Errorf(codes.InvalidArgument, "authorization should contain only 1 value")
+		}
+		scheme, token, ok := strings.Cut(v[0], " ")
+		if !ok || !strings.EqualFold(scheme, "bearer") || token == "" {
+			return "", status.Errorf(codes.Unauthenticated, "authorization should use the Bearer scheme")
+		}
+		return token, nil
+	}
+	t, ok := md[authTokenKey]
+	if !ok {
+		return "", status.Errorf(codes.Unauthenticated, "failed to get auth token")
+	}
+	if len(t) != 1 {
+		return "", status.Errorf(codes.InvalidArgument, "auth_token should contain only 1 value")
 	}
-	return ctx, nil
+	return t[0], nil
 }
 
 func logCalls(l *log.Logger) logging.Logger {
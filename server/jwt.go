@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// jwtClockSkew is the tolerance applied to the exp and nbf claims,
+	// for the clocks of the issuer and of the server to differ.
+	jwtClockSkew = time.Minute
+	// minRSAKeySize is the minimum size in bits of the RSA keys of a JWKS.
+	minRSAKeySize = 2048
+)
+
+// jwtAuthenticator accepts the JWTs signed with RS256 or ES256 by one of
+// the keys of a JWKS, until they expire.
+type jwtAuthenticator struct {
+	// keys are indexed by key id, "" for the keys without one.
+	keys map[string]jwtKey
+	// issuer and audience, if not empty, are the values required in
+	// the iss and aud claims.
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// jwtKey is a public key of a JWKS, with the algorithm it verifies.
+type jwtKey struct {
+	alg string
+	pub crypto.PublicKey
+}
+
+// jwk is a JSON Web Key, as defined by RFC 7517 and RFC 7518.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// newJWTAuthenticator reads the JWKS file at path. The tokens should be
+// issued by issuer, and be intended for audience, unless they are empty.
+func newJWTAuthenticator(path, issuer, audience string) (Authenticator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	keys, err := parseJWKS(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &jwtAuthenticator{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		now:      time.Now,
+	}, nil
+}
+
+// parseJWKS returns the signature keys of a JWK set. The keys of other
+// types, or for other algorithms, are ignored.
+func parseJWKS(r io.Reader) (map[string]jwtKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(r).Decode(&set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]jwtKey)
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var (
+			key jwtKey
+			err error
+		)
+		switch {
+		case k.Kty == "RSA" && (k.Alg == "" || k.Alg == "RS256"):
+			key.alg = "RS256"
+			key.pub, err = k.rsaPublicKey()
+		case k.Kty == "EC" && (k.Alg == "" || k.Alg == "ES256"):
+			key.alg = "ES256"
+			key.pub, err = k.ecdsaPublicKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		if _, ok := keys[k.Kid]; ok {
+			return nil, fmt.Errorf("key %d: duplicate key id %q", i, k.Kid)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no RS256 or ES256 signature key")
+	}
+	return keys, nil
+}
+
+func (k *jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid n: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("invalid e")
+	}
+	pub := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+	if pub.N.BitLen() < minRSAKeySize {
+		return nil, fmt.Errorf("RSA key should be at least %d bits long", minRSAKeySize)
+	}
+	return pub, nil
+}
+
+func (k *jwk) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	if k.Crv != "P-256" {
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+	x, errX := base64.RawURLEncoding.DecodeString(k.X)
+	y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+	if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("invalid coordinates")
+	}
+	// checks that the point is on the curve
+	point := append(append([]byte{4}, x...), y...)
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
+
+// jwtHeader is the JOSE header of a JWT.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwtClaims are the registered claims checked by jwtAuthenticator.
+type jwtClaims struct {
+	Subject   string        `json:"sub"`
+	Issuer    string        `json:"iss"`
+	Audience  audienceClaim `json:"aud"`
+	Expiry    *float64      `json:"exp"`
+	NotBefore *float64      `json:"nbf"`
+}
+
+// audienceClaim is the aud claim, either a string or an array of strings.
+type audienceClaim []string
+
+func (a *audienceClaim) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audienceClaim{s}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(a))
+}
+
+func (a *jwtAuthenticator) Authenticate(_ context.Context, token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed", errInvalidToken)
+	}
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", errInvalidToken)
+	}
+	key, ok := a.keys[header.Kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", errInvalidToken, header.Kid)
+	}
+	// the algorithm is the one of the key, never the one chosen by the
+	// token ("none" included).
+	if header.Alg != key.alg {
+		return nil, fmt.Errorf("%w: unexpected algorithm %q", errInvalidToken, header.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", errInvalidToken)
+	}
+	if !verifyJWT(key, parts[0]+"."+parts[1], sig) {
+		return nil, fmt.Errorf("%w: bad signature", errInvalidToken)
+	}
+
+	var (
+		claims jwtClaims
+		all    map[string]any
+	)
+	if decodeJWTPart(parts[1], &claims) != nil || decodeJWTPart(parts[1], &all) != nil {
+		return nil, fmt.Errorf("%w: malformed claims", errInvalidToken)
+	}
+	if err := a.checkClaims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidToken, err)
+	}
+	return &Principal{Subject: claims.Subject, Claims: all}, nil
+}
+
+func (a *jwtAuthenticator) checkClaims(c *jwtClaims) error {
+	now := a.now()
+	switch {
+	case c.Subject == "":
+		return errors.New("missing sub")
+	case c.Expiry == nil:
+		return errors.New("missing exp")
+	case !now.Add(-jwtClockSkew).Before(numericDate(*c.Expiry)):
+		return errors.New("expired")
+	case c.NotBefore != nil && now.Add(jwtClockSkew).Before(numericDate(*c.NotBefore)):
+		return errors.New("not valid yet")
+	case a.issuer != "" && c.Issuer != a.issuer:
+		return fmt.Errorf("unexpected issuer %q", c.Issuer)
+	case a.audience != "" && !slices.Contains(c.Audience, a.audience):
+		return fmt.Errorf("not intended for %q", a.audience)
+	}
+	return nil
+}
+
+// numericDate converts the seconds since the epoch of a JWT date.
+func numericDate(s float64) time.Time {
+	sec, frac := math.Modf(s)
+	return time.Unix(int64(sec), int64(frac*float64(time.Second)))
+}
+
+// decodeJWTPart decodes the base64 JSON of a header or of claims.
+func decodeJWTPart(part string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+// verifyJWT checks the signature of the signing input of a JWT, its
+// header and claims.
+func verifyJWT(key jwtKey, input string, sig []byte) bool {
+	hash := sha256.Sum256([]byte(input))
+	switch pub := key.pub.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], sig) == nil
+	case *ecdsa.PublicKey:
+		// r and s, 32 bytes each, rather than ASN.1
+		if len(sig) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(pub, hash[:], r, s)
+	}
+	return false
+}
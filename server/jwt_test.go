@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// jwtSigner mints tokens for the tests with a locally generated key.
+type jwtSigner struct {
+	kid string
+	key crypto.Signer
+}
+
+func newRSASigner(t *testing.T, kid string) *jwtSigner {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &jwtSigner{kid: kid, key: key}
+}
+
+func newECDSASigner(t *testing.T, kid string) *jwtSigner {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &jwtSigner{kid: kid, key: key}
+}
+
+func (s *jwtSigner) alg() string {
+	if _, ok := s.key.(*rsa.PrivateKey); ok {
+		return "RS256"
+	}
+	return "ES256"
+}
+
+// jwk returns the public key of s in a JWKS.
+func (s *jwtSigner) jwk() jwk {
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		return jwk{Kty: "RSA", Kid: s.kid, N: b64(key.N.Bytes()), E: b64(big.NewInt(int64(key.E)).Bytes())}
+	case *ecdsa.PrivateKey:
+		return jwk{Kty: "EC", Kid: s.kid, Crv: "P-256", X: b64(key.X.FillBytes(make([]byte, 32))), Y: b64(key.Y.FillBytes(make([]byte, 32)))}
+	}
+	panic("unexpected key")
+}
+
+// sign returns a token with header h, merged into the default header of
+// s, and claims.
+func (s *jwtSigner) sign(t *testing.T, h map[string]any, claims map[string]any) string {
+	t.Helper()
+	header := map[string]any{"alg": s.alg(), "typ": "JWT", "kid": s.kid}
+	for k, v := range h {
+		header[k] = v
+	}
+	part := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	input := part(header) + "." + part(claims)
+	hash := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:]); err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func writeJWKS(t *testing.T, keys ...jwk) string {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	rsaSigner := newRSASigner(t, "rsa")
+	ecSigner := newECDSASigner(t, "ec")
+	unknown := newECDSASigner(t, "ec")
+	// keys for other uses are ignored
+	encryption := newECDSASigner(t, "enc").jwk()
+	encryption.Use = "enc"
+
+	a, err := newJWTAuthenticator(writeJWKS(t, rsaSigner.jwk(), ecSigner.jwk(), encryption), "https://issuer.example.com", "todo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a.(*jwtAuthenticator).now = func() time.Time { return now }
+
+	claims := func(override map[string]any) map[string]any {
+		c := map[string]any{
+			"sub":   "alice",
+			"iss":   "https://issuer.example.com",
+			"aud":   []string{"other", "todo"},
+			"exp":   now.Add(time.Hour).Unix(),
+			"nbf":   now.Add(-time.Hour).Unix(),
+			"roles": []string{"admin"},
+		}
+		for k, v := range override {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	for _, s := range []*jwtSigner{rsaSigner, ecSigner} {
+		t.Run(s.alg(), func(t *testing.T) {
+			p, err := a.Authenticate(context.TODO(), s.sign(t, nil, claims(map[string]any{"aud": "todo"})))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Subject != "alice" || p.Claims["iss"] != "https://issuer.example.com" {
+				t.Errorf("unexpected principal: %v", p)
+			}
+		})
+	}
+
+	valid := ecSigner.sign(t, nil, claims(nil))
+	header, _, _ := strings.Cut(valid, ".")
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Expired", ecSigner.sign(t, nil, claims(map[string]any{"exp": now.Add(-2 * time.Minute).Unix()}))},
+		{"NotValidYet", ecSigner.sign(t, nil, claims(map[string]any{"nbf": now.Add(2 * time.Minute).Unix()}))},
+		{"NoExpiry", ecSigner.sign(t, nil, claims(map[string]any{"exp": nil}))},
+		{"NoSubject", ecSigner.sign(t, nil, claims(map[string]any{"sub": nil}))},
+		{"OtherIssuer", ecSigner.sign(t, nil, claims(map[string]any{"iss": "https://other.example.com"}))},
+		{"OtherAudience", ecSigner.sign(t, nil, claims(map[string]any{"aud": "other"}))},
+		{"NoAudience", ecSigner.sign(t, nil, claims(map[string]any{"aud": nil}))},
+		{"UnknownKey", unknown.sign(t, map[string]any{"kid": "other"}, claims(nil))},
+		{"BadSignature", unknown.sign(t, nil, claims(nil))},
+		{"AlgNone", ecSigner.sign(t, map[string]any{"alg": "none"}, claims(nil))},
+		{"AlgMismatch", ecSigner.sign(t, map[string]any{"alg": "RS256"}, claims(nil))},
+		{"OtherKeyType", ecSigner.sign(t, map[string]any{"kid": "rsa", "alg": "RS256"}, claims(nil))},
+		{"EncryptionKey", newECDSASigner(t, "enc").sign(t, nil, claims(nil))},
+		{"Malformed", "not a jwt"},
+		{"MalformedHeader", "!!!" + valid[len(header):]},
+		{"MalformedSignature", valid + "!!!"},
+		{"TooManyParts", valid + ".part"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := a.Authenticate(context.TODO(), tt.token); !errors.Is(err, errInvalidToken) {
+				t.Errorf("expected %v, got %v", errInvalidToken, err)
+			}
+		})
+	}
+}
+
+func TestParseJWKSInvalid(t *testing.T) {
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec := newECDSASigner(t, "ec").jwk()
+	offCurve := ec
+	offCurve.Y = ec.X
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"NotJSON", "keys"},
+		{"NoKey", `{"keys": []}`},
+		{"OnlyUnsupported", `{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`},
+		{"SmallRSA", mustJSON(t, map[string]any{"keys": []jwk{(&jwtSigner{key: small}).jwk()}})},
+		{"OffCurve", mustJSON(t, map[string]any{"keys": []jwk{offCurve}})},
+		{"OtherCurve", `{"keys": [{"kty": "EC", "crv": "P-384", "x": "AA", "y": "AA"}]}`},
+		{"DuplicateKid", mustJSON(t, map[string]any{"keys": []jwk{ec, ec}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseJWKS(strings.NewReader(tt.in)); err == nil {
+				t.Errorf("expected an error parsing %s", tt.in)
+			}
+		})
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
@@ -10,10 +13,10 @@ import (
 	"syscall"
 	"time"
 
+	"golang.org/x/exp/slog"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 
 	grpcprom "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
@@ -26,22 +29,97 @@ import (
 )
 
 type server struct {
-	d db
+	d    db
+	opts serverOptions
 	pb.UnimplementedTodoServiceServer
 }
 
+func newServer(d db, opt ...ServerOption) *server {
+	opts := defaultServerOptions
+	for _, o := range opt {
+		o.apply(&opts)
+	}
+
+	return &server{
+		d:    d,
+		opts: opts,
+	}
+}
+
+var (
+	storage      = flag.String("storage", "sqlite", "storage backend: memory (lost on exit), sqlite, bolt or postgres")
+	dbPath       = flag.String("db-path", "todo.db", "path of the sqlite or bolt database file")
+	postgresDSN  = flag.String("postgres-dsn", "", "postgres connection string, defaults to the PG* environment variables")
+	listRate     = flag.Float64("list-rate", 0, "maximum tasks per second sent by each ListTasks call, 0 for no limit")
+	listBurst    = flag.Int("list-burst", 1, "tasks sent by each ListTasks call before -list-rate applies")
+	logLevel     = flag.String("log-level", "info", "minimum level of the logs: debug, info, warn or error")
+	authName     = flag.String("auth", "static", "authenticator of the clients: static, hmac, jwt, mtls (certificate only), or a custom one")
+	authConfig   = flag.String("auth-config", "./certs/auth_tokens", "file configuring -auth: the tokens for static, the key for hmac, the JWKS for jwt")
+	issueToken   = flag.String("issue-token", "", "print an hmac token for this subject, signed with the key of -auth-config, and exit")
+	tokenTTL     = flag.Duration("token-ttl", 24*time.Hour, "validity of the token printed by -issue-token")
+	jwtIssuer    = flag.String("jwt-issuer", "", "iss claim required in the tokens with -auth=jwt, if not empty")
+	jwtAudience  = flag.String("jwt-audience", "", "audience required in the aud claim of the tokens with -auth=jwt, if not empty")
+	tlsCert      = flag.String("tls-cert", "./certs/server_cert.pem", "PEM certificate of the server")
+	tlsKey       = flag.String("tls-key", "./certs/server_key.pem", "PEM private key of the server")
+	clientCA     = flag.String("client-ca", "", "PEM CA certificates the client certificates should be signed by, enables mTLS")
+	policyPath   = flag.String("policy", "", "YAML or JSON file of the roles allowed to call each method, every authenticated client can call every method if empty")
+	policyReload = flag.Duration("policy-reload", 10*time.Second, "interval the -policy file is checked for changes at")
+)
+
 func main() {
-	args := os.Args[1:]
+	flag.Parse()
+	if *issueToken != "" {
+		key, err := readHMACKey(*authConfig)
+		if err != nil {
+			log.Fatalf("failed to read hmac key: %v\n", err)
+		}
+		token, err := signHMACToken(key, *issueToken, time.Now().Add(*tokenTTL))
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(token)
+		return
+	}
+	args := flag.Args()
 	if len(args) != 2 {
-		log.Fatalln("usage: server [GRPC_IP_ADDR] [METRICS_IP_ADDR]")
+		log.Fatalln("usage: server [flags] [GRPC_IP_ADDR] [METRICS_IP_ADDR]")
 	}
 	grpcAddr := args[0]
 	httpAddr := args[1]
 
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		log.Fatalf("invalid -log-level: %v\n", err)
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	authn, err := newAuthenticator(*authName, *authConfig)
+	if err != nil {
+		log.Fatalf("failed to set up %s authentication: %v\n", *authName, err)
+	}
+	var authz *authorizer
+	if *policyPath != "" {
+		if authz, err = newAuthorizer(*policyPath, logger); err != nil {
+			log.Fatalf("failed to load the policy: %v\n", err)
+		}
+	}
+
 	ctx := context.Background()
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	source := *dbPath
+	if *storage == "postgres" {
+		source = *postgresDSN
+	}
+	d, err := openDB(ctx, *storage, source)
+	if err != nil {
+		log.Fatalf("failed to open %s storage: %v\n", *storage, err)
+	}
+	if c, ok := d.(io.Closer); ok {
+		defer c.Close()
+	}
+
 	lis, err := net.Listen("tcp", grpcAddr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v\n", grpcAddr)
@@ -57,7 +135,11 @@ func main() {
 		),
 	)
 
-	grpcSrv, err := newGrpcServer(lis, srvMetrics)
+	opts := []ServerOption{Logger(logger)}
+	if *listRate > 0 {
+		opts = append(opts, ListPacing(rate.Limit(*listRate), *listBurst))
+	}
+	grpcSrv, err := newGrpcServer(lis, srvMetrics, newServer(d, opts...), authn, authz)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -73,6 +155,12 @@ func main() {
 
 	reg := prometheus.NewRegistry()
 	reg.MustRegister(srvMetrics)
+	if authz != nil {
+		reg.MustRegister(authz)
+		g.Go(func() error {
+			return authz.watch(ctx, *policyReload)
+		})
+	}
 
 	metricsServer := newMetricsServer(httpAddr, reg)
 	g.Go(func() error {
@@ -106,35 +194,41 @@ func newMetricsServer(httpAddr string, reg *prometheus.Registry) *http.Server {
 	}
 }
 
-func newGrpcServer(lis net.Listener, srvMetrics *grpcprom.ServerMetrics) (*grpc.Server, error) {
+// newGrpcServer returns the gRPC server of srv. The calls are checked
+// by authz after being authenticated by authn, unless authz is nil.
+func newGrpcServer(lis net.Listener, srvMetrics *grpcprom.ServerMetrics, srv *server, authn Authenticator, authz *authorizer) (*grpc.Server, error) {
 	logger := log.New(os.Stderr, "", log.Ldate|log.Ltime)
 
-	creds, err := credentials.NewServerTLSFromFile("./certs/server_cert.pem", "./certs/server_key.pem")
+	creds, err := newServerCredentials(*tlsCert, *tlsKey, *clientCA)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	limiter := &simpleLimiter{
 		limiter: rate.NewLimiter(2, 4),
 	}
+	unary := []grpc.UnaryServerInterceptor{
+		ratelimit.UnaryServerInterceptor(limiter),
+		otelgrpc.UnaryServerInterceptor(),
+		srvMetrics.UnaryServerInterceptor(),
+		auth.UnaryServerInterceptor(authFunc(authn)),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		ratelimit.StreamServerInterceptor(limiter),
+		otelgrpc.StreamServerInterceptor(),
+		srvMetrics.StreamServerInterceptor(),
+		auth.StreamServerInterceptor(authFunc(authn)),
+	}
+	if authz != nil {
+		unary = append(unary, authz.UnaryServerInterceptor())
+		stream = append(stream, authz.StreamServerInterceptor())
+	}
 	opts := []grpc.ServerOption{
 		grpc.Creds(creds),
-		grpc.ChainUnaryInterceptor(
-			ratelimit.UnaryServerInterceptor(limiter),
-			otelgrpc.UnaryServerInterceptor(),
-			srvMetrics.UnaryServerInterceptor(),
-			auth.UnaryServerInterceptor(validateAuthToken),
-			logging.UnaryServerInterceptor(logCalls(logger)),
-		),
-		grpc.ChainStreamInterceptor(
-			ratelimit.StreamServerInterceptor(limiter),
-			otelgrpc.StreamServerInterceptor(),
-			srvMetrics.StreamServerInterceptor(),
-			auth.StreamServerInterceptor(validateAuthToken),
-			logging.StreamServerInterceptor(logCalls(logger)),
-		),
+		grpc.ChainUnaryInterceptor(append(unary, logging.UnaryServerInterceptor(logCalls(logger)))...),
+		grpc.ChainStreamInterceptor(append(stream, logging.StreamServerInterceptor(logCalls(logger)))...),
 	}
 	s := grpc.NewServer(opts...)
-	pb.RegisterTodoServiceServer(s, &server{d: New()})
+	pb.RegisterTodoServiceServer(s, srv)
 	return s, nil
 }
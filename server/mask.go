@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var taskDescriptor = (&pb.Task{}).ProtoReflect().Descriptor()
+
+// maskTree is a field mask compiled against a message descriptor.
+// Every key is the name of a field to keep. A nil subtree keeps the
+// whole field, otherwise the subtree is applied to the field's
+// message, or to every message of a repeated field.
+//
+// A nil maskTree keeps everything.
+type maskTree map[protoreflect.Name]maskTree
+
+// compileMask checks that every path of mask exists in md and returns
+// the corresponding tree. Paths are dot-separated field names, only
+// the last one may be a scalar or a map field.
+func compileMask(md protoreflect.MessageDescriptor, mask *fieldmaskpb.FieldMask) (maskTree, error) {
+	if len(mask.GetPaths()) == 0 {
+		return nil, nil
+	}
+
+	tree := maskTree{}
+	for _, path := range mask.Paths {
+		node, desc := tree, md
+		names := strings.Split(path, ".")
+		for i, name := range names {
+			if desc == nil {
+				return nil, fmt.Errorf("invalid path %q: %s is not a message", path, strings.Join(names[:i], "."))
+			}
+			fd := desc.Fields().ByName(protoreflect.Name(name))
+			if fd == nil {
+				return nil, fmt.Errorf("unknown %s field %q", desc.Name(), name)
+			}
+
+			sub, seen := node[fd.Name()]
+			if i == len(names)-1 {
+				// keeping the whole field, whatever was kept in it
+				node[fd.Name()] = nil
+				break
+			}
+			if seen && sub == nil {
+				// already kept as a whole by a shorter path
+				break
+			}
+			if !seen {
+				sub = maskTree{}
+				node[fd.Name()] = sub
+			}
+			node, desc = sub, nil
+			if !fd.IsMap() {
+				desc = fd.Message()
+			}
+		}
+	}
+	return tree, nil
+}
+
+// filter clears the fields of m not kept by t.
+func (t maskTree) filter(m protoreflect.Message) {
+	if t == nil {
+		return
+	}
+
+	var clear []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		sub, ok := t[fd.Name()]
+		switch {
+		case !ok:
+			clear = append(clear, fd)
+		case sub == nil:
+		case fd.IsList():
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				sub.filter(l.Get(i).Message())
+			}
+		default:
+			sub.filter(v.Message())
+		}
+		return true
+	})
+	for _, fd := range clear {
+		m.Clear(fd)
+	}
+}
+
+// updatableTaskFields are the Task fields UpdateTasks can change.
+var updatableTaskFields = []string{"description", "done", "due_date"}
+
+// defaultUpdateMask returns the mask used when an UpdateTasksRequest
+// doesn't have one: every updatable field is overwritten.
+func defaultUpdateMask() *fieldmaskpb.FieldMask {
+	return &fieldmaskpb.FieldMask{Paths: slices.Clone(updatableTaskFields)}
+}
+
+// validateUpdateMask checks that every path of mask is an updatable
+// Task field.
+func validateUpdateMask(mask *fieldmaskpb.FieldMask) error {
+	if _, err := compileMask(taskDescriptor, mask); err != nil {
+		return err
+	}
+	for _, path := range mask.GetPaths() {
+		if !slices.Contains(updatableTaskFields, path) {
+			return fmt.Errorf("field %q of Task cannot be updated", path)
+		}
+	}
+	return nil
+}
+
+// applyUpdate copies the fields of update listed in mask into task, and
+// increments its version. Fields listed in mask but not set in update
+// are cleared in task. The mask should have been validated with
+// validateUpdateMask.
+func applyUpdate(task, update *pb.Task, mask *fieldmaskpb.FieldMask) {
+	task.Version++
+	dst := task.ProtoReflect()
+	src := proto.Clone(update).ProtoReflect()
+	fields := dst.Descriptor().Fields()
+	for _, path := range mask.GetPaths() {
+		fd := fields.ByName(protoreflect.Name(path))
+		if src.Has(fd) {
+			dst.Set(fd, src.Get(fd))
+		} else {
+			dst.Clear(fd)
+		}
+	}
+}
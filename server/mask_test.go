@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestFilter(t *testing.T) {
+	task := func() *pb.Task {
+		return &pb.Task{
+			Id:          1,
+			Description: "test",
+			Done:        true,
+			DueDate:     &timestamppb.Timestamp{Seconds: 10, Nanos: 20},
+		}
+	}
+	tests := []struct {
+		name     string
+		msg      proto.Message
+		paths    []string
+		expected proto.Message
+	}{
+		{"NoMask", task(), nil, task()},
+		{"TopLevel", task(), []string{"id", "done"}, &pb.Task{Id: 1, Done: true}},
+		{"Nested", task(), []string{"id", "due_date.seconds"},
+			&pb.Task{Id: 1, DueDate: &timestamppb.Timestamp{Seconds: 10}}},
+		{"ShorterPathWins", task(), []string{"due_date.seconds", "due_date"},
+			&pb.Task{DueDate: &timestamppb.Timestamp{Seconds: 10, Nanos: 20}}},
+		{"Repeated",
+			&pb.UpdateTasksResponse{Applied: 1, Failures: []*pb.UpdateTasksResponse_Failure{
+				{Id: 1, Reason: "a"}, {Id: 2, Reason: "b"},
+			}},
+			[]string{"failures.id"},
+			&pb.UpdateTasksResponse{Failures: []*pb.UpdateTasksResponse_Failure{{Id: 1}, {Id: 2}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.msg.ProtoReflect()
+			mask, err := compileMask(m.Descriptor(), &fieldmaskpb.FieldMask{Paths: tt.paths})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			mask.filter(m)
+			if !proto.Equal(tt.msg, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, tt.msg)
+			}
+		})
+	}
+}
+
+func TestCompileMaskInvalidPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  string
+	}{
+		{"Unknown", "priority", `unknown Task field "priority"`},
+		{"UnknownNested", "due_date.minutes", `unknown Timestamp field "minutes"`},
+		{"Scalar", "description.length", `invalid path "description.length": description is not a message`},
+		{"Empty", "due_date.", `unknown Timestamp field ""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileMask(taskDescriptor, &fieldmaskpb.FieldMask{Paths: []string{"id", tt.path}})
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
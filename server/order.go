@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+)
+
+// orderField is the Task field tasks are sorted by.
+type orderField int
+
+const (
+	orderByID orderField = iota
+	orderByDueDate
+	orderByDescription
+	orderByDone
+)
+
+var orderFieldNames = map[orderField]string{
+	orderByID:          "id",
+	orderByDueDate:     "due_date",
+	orderByDescription: "description",
+	orderByDone:        "done",
+}
+
+func (f orderField) String() string {
+	return orderFieldNames[f]
+}
+
+// taskOrder is the storage side version of pb.TaskOrder. The zero value
+// sorts by ascending id.
+//
+// Ties are broken by id, in the same direction, so that the order is
+// total and a listing can be resumed after any task. Tasks without due
+// date sort as if due after all the others.
+type taskOrder struct {
+	field orderField
+	desc  bool
+}
+
+// newTaskOrder converts o.
+func newTaskOrder(o *pb.TaskOrder) (taskOrder, error) {
+	var to taskOrder
+	switch o.GetField() {
+	case pb.TaskOrder_FIELD_UNSPECIFIED, pb.TaskOrder_FIELD_ID:
+		to.field = orderByID
+	case pb.TaskOrder_FIELD_DUE_DATE:
+		to.field = orderByDueDate
+	case pb.TaskOrder_FIELD_DESCRIPTION:
+		to.field = orderByDescription
+	case pb.TaskOrder_FIELD_DONE:
+		to.field = orderByDone
+	default:
+		return to, fmt.Errorf("unknown field %d", o.GetField())
+	}
+	to.desc = o.GetDescending()
+	return to, nil
+}
+
+// compare returns a negative number if a comes before b, a positive one
+// if it comes after and 0 if they have the same id.
+func (o taskOrder) compare(a, b *pb.Task) int {
+	c := 0
+	switch o.field {
+	case orderByDueDate:
+		c = compareDueDates(a, b)
+	case orderByDescription:
+		c = strings.Compare(a.Description, b.Description)
+	case orderByDone:
+		c = compareBools(a.Done, b.Done)
+	}
+	if c == 0 {
+		c = compareUint64s(a.Id, b.Id)
+	}
+	if o.desc {
+		return -c
+	}
+	return c
+}
+
+func compareDueDates(a, b *pb.Task) int {
+	switch {
+	case a.DueDate == nil && b.DueDate == nil:
+		return 0
+	case a.DueDate == nil:
+		return 1
+	case b.DueDate == nil:
+		return -1
+	default:
+		return a.DueDate.AsTime().Compare(b.DueDate.AsTime())
+	}
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case b:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func compareUint64s(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+)
+
+func TestNewTaskOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    *pb.TaskOrder
+		expected taskOrder
+	}{
+		{"Nil", nil, taskOrder{}},
+		{"Unspecified", &pb.TaskOrder{Descending: true}, taskOrder{desc: true}},
+		{"DueDate", &pb.TaskOrder{Field: pb.TaskOrder_FIELD_DUE_DATE}, taskOrder{field: orderByDueDate}},
+		{"Done", &pb.TaskOrder{Field: pb.TaskOrder_FIELD_DONE, Descending: true}, taskOrder{field: orderByDone, desc: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTaskOrder(tt.order)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := newTaskOrder(&pb.TaskOrder{Field: 42}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxPageSize caps the page_size of ListTasksRequest.
+const maxPageSize = 1000
+
+// pageToken is the position of a listing. It is encoded as base64 JSON
+// so that clients treat it as opaque, but can be decoded by replicas
+// running another version.
+//
+// Tokens hold the sort key of the last task returned, not an offset, so
+// they stay valid when tasks are added, updated or deleted, even the one
+// they refer to.
+type pageToken struct {
+	// OrderBy and Desc are the order of the listing, a token cannot
+	// resume a listing in another order.
+	OrderBy string `json:"order_by"`
+	Desc    bool   `json:"desc,omitempty"`
+	// After is the id of the last task returned. Only the field of
+	// that task sorted by is set among the following ones.
+	After       uint64     `json:"after"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Done        bool       `json:"done,omitempty"`
+}
+
+// newPageToken returns the token resuming a listing in order o after
+// task.
+func newPageToken(o taskOrder, task *pb.Task) pageToken {
+	t := pageToken{
+		OrderBy: o.field.String(),
+		Desc:    o.desc,
+		After:   task.Id,
+	}
+	switch o.field {
+	case orderByDueDate:
+		if task.DueDate != nil {
+			dueDate := task.DueDate.AsTime()
+			t.DueDate = &dueDate
+		}
+	case orderByDescription:
+		t.Description = task.Description
+	case orderByDone:
+		t.Done = task.Done
+	}
+	return t
+}
+
+// resumes reports whether t can resume a listing in order o.
+func (t pageToken) resumes(o taskOrder) bool {
+	return t.OrderBy == o.field.String() && t.Desc == o.desc
+}
+
+// cursor returns the task a listing resumes after, to be used as
+// taskQuery.after.
+func (t pageToken) cursor() *pb.Task {
+	task := &pb.Task{
+		Id:          t.After,
+		Description: t.Description,
+		Done:        t.Done,
+	}
+	if t.DueDate != nil {
+		task.DueDate = timestamppb.New(*t.DueDate)
+	}
+	return task
+}
+
+func (t pageToken) encode() string {
+	// marshalling a struct without maps, channels or floats cannot fail
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodePageToken decodes s, the zero token is returned if s is empty.
+func decodePageToken(s string) (pageToken, error) {
+	var t pageToken
+	if s == "" {
+		return t, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return t, errors.New("malformed token")
+	}
+	if err := json.Unmarshal(b, &t); err != nil || t.After == 0 {
+		return t, errors.New("malformed token")
+	}
+	return t, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestPageToken(t *testing.T) {
+	dueDate := time.Date(2023, 8, 1, 12, 0, 0, 42, time.UTC)
+	task := &pb.Task{Id: 42, Description: "test", Done: true, DueDate: timestamppb.New(dueDate)}
+	tests := []struct {
+		name   string
+		order  taskOrder
+		last   *pb.Task
+		cursor *pb.Task
+	}{
+		{"ID", taskOrder{}, task, &pb.Task{Id: 42}},
+		{"DueDate", taskOrder{field: orderByDueDate, desc: true}, task, &pb.Task{Id: 42, DueDate: timestamppb.New(dueDate)}},
+		{"NoDueDate", taskOrder{field: orderByDueDate}, &pb.Task{Id: 43}, &pb.Task{Id: 43}},
+		{"Description", taskOrder{field: orderByDescription}, task, &pb.Task{Id: 42, Description: "test"}},
+		{"Done", taskOrder{field: orderByDone}, task, &pb.Task{Id: 42, Done: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePageToken(newPageToken(tt.order, tt.last).encode())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.resumes(tt.order) {
+				t.Errorf("expected token to resume order %v", tt.order)
+			}
+			if !proto.Equal(got.cursor(), tt.cursor) {
+				t.Errorf("expected cursor %v, got %v", tt.cursor, got.cursor())
+			}
+		})
+	}
+}
+
+func TestPageTokenOtherOrder(t *testing.T) {
+	token := newPageToken(taskOrder{field: orderByDone}, &pb.Task{Id: 1})
+	for _, o := range []taskOrder{{}, {field: orderByDone, desc: true}, {field: orderByDescription}} {
+		if token.resumes(o) {
+			t.Errorf("expected token not to resume order %v", o)
+		}
+	}
+}
+
+func TestDecodePageTokenInvalid(t *testing.T) {
+	if got, err := decodePageToken(""); err != nil || got != (pageToken{}) {
+		t.Errorf("expected the zero token, got %v, %v", got, err)
+	}
+
+	for _, s := range []string{"not base64!", "bm90IGpzb24", "e30"} { // "not json", "{}"
+		if _, err := decodePageToken(s); err == nil {
+			t.Errorf("expected an error decoding %q", s)
+		}
+	}
+}
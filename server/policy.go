@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/exp/slices"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+// rolesClaim is the claim of the principals, set by the jwt
+// authenticator, listing roles given by the issuer of the token.
+const rolesClaim = "roles"
+
+// peerBindingPrefix prefixes the bindings of the identities of client
+// certificates, rather than of subjects.
+const peerBindingPrefix = "peer:"
+
+// policy maps the authenticated subjects to the methods they may call,
+// through roles. It is read from a YAML or JSON file such as:
+//
+//	roles:
+//	  reader:
+//	    - /todo.v2.TodoService/GetTask
+//	    - /todo.v2.TodoService/ListTasks
+//	    - /todo.v2.TodoService/WatchTasks
+//	  writer:
+//	    - /todo.v2.TodoService/*
+//	bindings:
+//	  demo: [writer]
+//	  peer:spiffe://example.com/ns/todo/sa/admin: [writer]
+//	default: [reader]
+//
+// Methods are full method names, "/<service>/*" for all the methods of
+// a service, or "*" for all the methods. Every principal has the
+// default roles, the ones its subject is bound to, the ones the
+// identity of its client certificate is bound to with the "peer:"
+// prefix, and the ones listed in its roles claim which are defined by
+// the policy.
+type policy struct {
+	Roles    map[string][]string `yaml:"roles"`
+	Bindings map[string][]string `yaml:"bindings"`
+	Default  []string            `yaml:"default"`
+}
+
+// parsePolicy reads a policy, JSON being a subset of YAML. Unknown
+// fields, and bindings to undefined roles, are rejected as they are
+// likely typos.
+func parsePolicy(r io.Reader) (*policy, error) {
+	d := yaml.NewDecoder(r)
+	d.KnownFields(true)
+	var p policy
+	if err := d.Decode(&p); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("empty policy")
+		}
+		return nil, err
+	}
+	for role, methods := range p.Roles {
+		for _, m := range methods {
+			if !validMethodPattern(m) {
+				return nil, fmt.Errorf("role %q: invalid method %q", role, m)
+			}
+		}
+	}
+	check := func(where string, roles []string) error {
+		for _, role := range roles {
+			if _, ok := p.Roles[role]; !ok {
+				return fmt.Errorf("%s: undefined role %q", where, role)
+			}
+		}
+		return nil
+	}
+	if err := check("default", p.Default); err != nil {
+		return nil, err
+	}
+	for subject, roles := range p.Bindings {
+		if err := check(fmt.Sprintf("binding of %q", subject), roles); err != nil {
+			return nil, err
+		}
+	}
+	return &p, nil
+}
+
+// validMethodPattern reports whether m is "*", or "/<service>/<method>"
+// with method possibly being "*".
+func validMethodPattern(m string) bool {
+	if m == "*" {
+		return true
+	}
+	service, method, ok := strings.Cut(strings.TrimPrefix(m, "/"), "/")
+	return ok && strings.HasPrefix(m, "/") && service != "" && method != "" && !strings.Contains(method, "/")
+}
+
+// matchMethod reports whether the full method name method is matched
+// by pattern.
+func matchMethod(pattern, method string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if service, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(method, service+"/")
+	}
+	return pattern == method
+}
+
+// allows reports whether p may call the full method name method.
+func (pol *policy) allows(p *Principal, method string) bool {
+	for _, role := range pol.roles(p) {
+		for _, pattern := range pol.Roles[role] {
+			if matchMethod(pattern, method) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// roles returns the roles of p, possibly with duplicates.
+func (pol *policy) roles(p *Principal) []string {
+	roles := slices.Clone(pol.Default)
+	roles = append(roles, pol.Bindings[p.Subject]...)
+	if p.Peer != "" {
+		roles = append(roles, pol.Bindings[peerBindingPrefix+p.Peer]...)
+	}
+	// a single role or a list of them
+	switch claim := p.Claims[rolesClaim].(type) {
+	case string:
+		roles = append(roles, claim)
+	case []any:
+		for _, c := range claim {
+			if role, ok := c.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
+
+// authorizer checks the calls against the policy of a file, reloaded
+// by watch when the file changes. It is a prometheus.Collector of the
+// calls denied.
+type authorizer struct {
+	path   string
+	logger *slog.Logger
+	policy atomic.Pointer[policy]
+	// content of the file the policy was loaded from, only used by
+	// reload.
+	loaded []byte
+	denied *prometheus.CounterVec
+}
+
+// newAuthorizer loads the policy of the file at path.
+func newAuthorizer(path string, logger *slog.Logger) (*authorizer, error) {
+	a := &authorizer{
+		path:   path,
+		logger: logger,
+		denied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_authz_denied_total",
+			Help: "Total number of RPCs denied by the authorization policy.",
+		}, []string{"grpc_service", "grpc_method"}),
+	}
+	if _, err := a.reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// reload loads the policy again if the file changed since it was last
+// loaded, and reports whether it did. The current policy is kept if the
+// new one is invalid.
+func (a *authorizer) reload() (bool, error) {
+	b, err := os.ReadFile(a.path)
+	if err != nil {
+		return false, err
+	}
+	if a.loaded != nil && bytes.Equal(b, a.loaded) {
+		return false, nil
+	}
+	p, err := parsePolicy(bytes.NewReader(b))
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", a.path, err)
+	}
+	a.policy.Store(p)
+	a.loaded = b
+	return true, nil
+}
+
+// watch reloads the policy every interval until ctx is done. The file is
+// read rather than its modification time checked, Kubernetes updates
+// mounted ConfigMaps by swapping a symlink.
+func (a *authorizer) watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		changed, err := a.reload()
+		if err != nil {
+			a.logger.ErrorContext(ctx, "failed to reload the policy, keeping the current one", "error", err)
+			continue
+		}
+		if changed {
+			a.logger.InfoContext(ctx, "reloaded the policy", "path", a.path)
+		}
+	}
+}
+
+// authorize returns a PermissionDenied error if the principal of ctx
+// may not call method.
+func (a *authorizer) authorize(ctx context.Context, method string) error {
+	p, ok := principalFromContext(ctx)
+	if ok && a.policy.Load().allows(p, method) {
+		return nil
+	}
+	service, name, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	a.denied.WithLabelValues(service, name).Inc()
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "unauthenticated calls of %s are not allowed", method)
+	}
+	return status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s", p.Subject, method)
+}
+
+// UnaryServerInterceptor checks the unary calls, after the auth
+// interceptor.
+func (a *authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor checks the streaming calls, after the auth
+// interceptor.
+func (a *authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (a *authorizer) Describe(ch chan<- *prometheus.Desc) {
+	a.denied.Describe(ch)
+}
+
+func (a *authorizer) Collect(ch chan<- prometheus.Metric) {
+	a.denied.Collect(ch)
+}
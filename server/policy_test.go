@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	grpcauth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
+	dto "github.com/prometheus/client_model/go"
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const testPolicy = `
+roles:
+  reader:
+    - /todo.v2.TodoService/GetTask
+    - /todo.v2.TodoService/ListTasks
+  writer:
+    - /todo.v2.TodoService/*
+  admin:
+    - "*"
+bindings:
+  alice: [writer]
+  peer:admin.example.com: [admin]
+default: [reader]
+`
+
+const (
+	methodGetTask    = "/todo.v2.TodoService/GetTask"
+	methodAddTask    = "/todo.v2.TodoService/AddTask"
+	methodDeleteTask = "/todo.v2.TodoService/DeleteTasks"
+	methodOther      = "/other.Service/Method"
+)
+
+func TestParsePolicy(t *testing.T) {
+	jsonPolicy := `{"roles": {"reader": ["/todo.v2.TodoService/GetTask"]}, "default": ["reader"]}`
+	for name, in := range map[string]string{"YAML": testPolicy, "JSON": jsonPolicy} {
+		t.Run(name, func(t *testing.T) {
+			p, err := parsePolicy(strings.NewReader(in))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !p.allows(&Principal{Subject: "bob"}, methodGetTask) {
+				t.Errorf("expected the default role to allow %s", methodGetTask)
+			}
+		})
+	}
+
+	p, err := parsePolicy(strings.NewReader(testPolicy))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name      string
+		principal *Principal
+		method    string
+		expected  bool
+	}{
+		{"Default", &Principal{Subject: "bob"}, methodGetTask, true},
+		{"DefaultDenied", &Principal{Subject: "bob"}, methodAddTask, false},
+		{"Binding", &Principal{Subject: "alice"}, methodDeleteTask, true},
+		{"ServiceWildcard", &Principal{Subject: "alice"}, methodOther, false},
+		{"ClaimList", &Principal{Subject: "bob", Claims: map[string]any{"roles": []any{"unknown", "admin"}}}, methodOther, true},
+		{"ClaimString", &Principal{Subject: "bob", Claims: map[string]any{"roles": "writer"}}, methodAddTask, true},
+		{"Peer", &Principal{Subject: "bob", Peer: "admin.example.com"}, methodOther, true},
+		{"PeerNotSubject", &Principal{Subject: "admin.example.com"}, methodOther, false},
+		{"OtherPeer", &Principal{Subject: "bob", Peer: "client.example.com"}, methodAddTask, false},
+		{"ClaimOtherType", &Principal{Subject: "bob", Claims: map[string]any{"roles": 1}}, methodAddTask, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.allows(tt.principal, tt.method); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParsePolicyInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"Empty", ""},
+		{"NotYAML", "roles: ["},
+		{"UnknownField", "roles: {}\nbinding: {}\n"},
+		{"UndefinedDefault", "roles: {}\ndefault: [reader]\n"},
+		{"UndefinedBinding", "roles: {reader: []}\nbindings: {alice: [writer]}\n"},
+		{"NoSlash", "roles: {reader: [todo.v2.TodoService/GetTask]}\n"},
+		{"NoMethod", "roles: {reader: [/todo.v2.TodoService]}\n"},
+		{"TooManyParts", "roles: {reader: [/todo/v2/GetTask]}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parsePolicy(strings.NewReader(tt.in)); err == nil {
+				t.Errorf("expected an error parsing %q", tt.in)
+			}
+		})
+	}
+}
+
+func TestAuthorizerReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(testPolicy)
+	a, err := newAuthorizer(path, slog.Default())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bob := &Principal{Subject: "bob"}
+	if a.policy.Load().allows(bob, methodAddTask) {
+		t.Fatalf("expected bob not to be allowed to call %s", methodAddTask)
+	}
+
+	if changed, err := a.reload(); err != nil || changed {
+		t.Errorf("expected an unchanged policy, got %v, %v", changed, err)
+	}
+	write(strings.Replace(testPolicy, "alice: [writer]", "alice: [writer]\n  bob: [writer]", 1))
+	if changed, err := a.reload(); err != nil || !changed {
+		t.Fatalf("expected the policy to be reloaded, got %v, %v", changed, err)
+	}
+	if !a.policy.Load().allows(bob, methodAddTask) {
+		t.Errorf("expected bob to be allowed to call %s after reload", methodAddTask)
+	}
+
+	// the invalid policy isn't applied
+	write("roles: [")
+	if _, err := a.reload(); err == nil {
+		t.Error("expected an error reloading an invalid policy")
+	}
+	if !a.policy.Load().allows(bob, methodAddTask) {
+		t.Errorf("expected the previous policy to be kept")
+	}
+
+	if _, err := newAuthorizer(filepath.Join(t.TempDir(), "missing"), slog.Default()); err == nil {
+		t.Error("expected an error for a missing policy")
+	}
+}
+
+func TestAuthorizerInterceptors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(testPolicy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	authz, err := newAuthorizer(path, slog.Default())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the token is the subject
+	a := AuthenticatorFunc(func(_ context.Context, token string) (*Principal, error) {
+		return &Principal{Subject: token}, nil
+	})
+	c := startGrpcServer(t, []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(grpcauth.UnaryServerInterceptor(authFunc(a)), authz.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(grpcauth.StreamServerInterceptor(authFunc(a)), authz.StreamServerInterceptor()),
+	}, New())
+	as := func(subject string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), authTokenKey, subject)
+	}
+	denied := func(method string) float64 {
+		t.Helper()
+		var m dto.Metric
+		if err := authz.denied.WithLabelValues("todo.v2.TodoService", method).Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetCounter().GetValue()
+	}
+
+	add := &pb.AddTaskRequest{Description: "task", DueDate: timestamppb.New(time.Now().Add(time.Hour))}
+	if _, err := c.AddTask(as("alice"), add); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.AddTask(as("bob"), add); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected %v, got %v", codes.PermissionDenied, err)
+	}
+	if n := denied("AddTask"); n != 1 {
+		t.Errorf("expected 1 denied AddTask, got %v", n)
+	}
+
+	// streams, bob has no task
+	stream, err := c.ListTasks(as("bob"), &pb.ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("expected %v, got %v", io.EOF, err)
+	}
+	watch, err := c.WatchTasks(as("bob"), &pb.WatchTasksRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := watch.Recv(); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected %v, got %v", codes.PermissionDenied, err)
+	}
+	if n := denied("WatchTasks"); n != 1 {
+		t.Errorf("expected 1 denied WatchTasks, got %v", n)
+	}
+}
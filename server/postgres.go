@@ -0,0 +1,484 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// postgresBatchSize is the number of tasks getTasks reads per query.
+const postgresBatchSize = 64
+
+// postgresMigrationLock is the advisory lock taken while migrating, so
+// that replicas starting together don't apply the same migration.
+const postgresMigrationLock = 0x746f646f // "todo"
+
+// postgresEventsLock is the advisory lock taken while recording a
+// change, so that revisions are contiguous and committed in order.
+const postgresEventsLock = 0x65766e74 // "evnt"
+
+// postgresMigrations are applied in order on startup and recorded in
+// the schema_migrations table. They must never be edited or reordered,
+// only appended.
+var postgresMigrations = []string{
+	// identity columns are backed by a sequence, ids of deleted tasks
+	// are never reused.
+	`CREATE TABLE tasks (
+		id          BIGINT      GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+		description TEXT        NOT NULL,
+		done        BOOLEAN     NOT NULL DEFAULT false,
+		due_date    TIMESTAMPTZ NOT NULL
+	)`,
+	// tasks can be updated to have no due date
+	`ALTER TABLE tasks ALTER COLUMN due_date DROP NOT NULL`,
+	// change feed of WatchTasks. Revisions are allocated under
+	// postgresEventsLock, not by a sequence which could leave gaps.
+	`CREATE TABLE task_events (
+		revision BIGINT   PRIMARY KEY,
+		kind     SMALLINT NOT NULL, -- eventKind
+		task     BYTEA    NOT NULL  -- marshalled todo.v2.Task
+	)`,
+	// optimistic concurrency, incremented by every update
+	`ALTER TABLE tasks ADD COLUMN version BIGINT NOT NULL DEFAULT 1`,
+	// tasks belong to the subject which added them, the ones added
+	// before have no owner.
+	`ALTER TABLE tasks ADD COLUMN owner TEXT NOT NULL DEFAULT ''`,
+	`CREATE INDEX tasks_owner ON tasks (owner, id)`,
+}
+
+// postgresDB is a db shared by all the server replicas connected to the
+// same PostgreSQL database.
+type postgresDB struct {
+	pool    *pgxpool.Pool
+	changes notifier
+}
+
+// newPostgresDB connects to the PostgreSQL database described by dsn and
+// migrates it to the latest schema. An empty dsn falls back to the
+// standard PG* environment variables. The pool is configured with the
+// pool_* dsn parameters (e.g. pool_max_conns).
+func newPostgresDB(ctx context.Context, dsn string) (*postgresDB, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	d := &postgresDB{pool: pool}
+	if err := d.migrate(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed migrating: %w", err)
+	}
+	return d, nil
+}
+
+func (d *postgresDB) migrate(ctx context.Context) error {
+	return pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", postgresMigrationLock); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL)"); err != nil {
+			return err
+		}
+
+		var version int
+		if err := tx.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version); err != nil {
+			return err
+		}
+		if version > len(postgresMigrations) {
+			return fmt.Errorf("unknown schema version %d", version)
+		}
+		for i, m := range postgresMigrations[version:] {
+			if _, err := tx.Exec(ctx, m); err != nil {
+				return fmt.Errorf("migration %d: %w", version+i+1, err)
+			}
+			if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version+i+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *postgresDB) Close() error {
+	d.pool.Close()
+	return nil
+}
+
+func (d *postgresDB) addTask(ctx context.Context, owner, description string, dueDate time.Time) (uint64, error) {
+	ids, err := d.addTasks(ctx, []newTask{{owner, description, dueDate}})
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+func (d *postgresDB) addTasks(ctx context.Context, tasks []newTask) ([]uint64, error) {
+	var ids []uint64
+	err := d.write(ctx, func(tx pgx.Tx) error {
+		ids = make([]uint64, 0, len(tasks))
+		for _, t := range tasks {
+			// the due date is returned as stored, in microseconds
+			var (
+				id      int64
+				dueDate time.Time
+			)
+			err := tx.QueryRow(ctx,
+				"INSERT INTO tasks (description, due_date, owner) VALUES ($1, $2, $3) RETURNING id, due_date",
+				t.description, t.dueDate, t.owner,
+			).Scan(&id, &dueDate)
+			if err != nil {
+				return err
+			}
+			err = recordPostgresEvent(ctx, tx, eventCreated, &pb.Task{
+				Id:          uint64(id),
+				Description: t.description,
+				DueDate:     timestamppb.New(dueDate),
+				Version:     1,
+				Owner:       t.owner,
+			})
+			if err != nil {
+				return err
+			}
+			ids = append(ids, uint64(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// write runs fn in a transaction and wakes up the watchers of this
+// replica once it is committed.
+func (d *postgresDB) write(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	if err := pgx.BeginFunc(ctx, d.pool, fn); err != nil {
+		return postgresError(err)
+	}
+	d.changes.notify()
+	return nil
+}
+
+// recordPostgresEvent appends a change of task to the change feed,
+// discarding the changes older than eventRetention.
+func recordPostgresEvent(ctx context.Context, tx pgx.Tx, kind eventKind, task *pb.Task) error {
+	b, err := proto.Marshal(task)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", postgresEventsLock); err != nil {
+		return err
+	}
+	var rev int64
+	err = tx.QueryRow(ctx,
+		"INSERT INTO task_events (revision, kind, task) SELECT COALESCE(MAX(revision), 0) + 1, $1, $2 FROM task_events RETURNING revision",
+		int16(kind), b,
+	).Scan(&rev)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, "DELETE FROM task_events WHERE revision <= $1", rev-eventRetention)
+	return err
+}
+
+func scanTask(row pgx.Row) (*pb.Task, error) {
+	var (
+		id      int64
+		task    pb.Task
+		dueDate *time.Time
+	)
+	if err := row.Scan(&id, &task.Description, &task.Done, &dueDate, &task.Version, &task.Owner); err != nil {
+		return nil, err
+	}
+	task.Id = uint64(id)
+	if dueDate != nil {
+		task.DueDate = timestamppb.New(*dueDate)
+	}
+	return &task, nil
+}
+
+func (d *postgresDB) getTask(ctx context.Context, id uint64) (*pb.Task, error) {
+	task, err := scanTask(d.pool.QueryRow(ctx,
+		"SELECT id, description, done, due_date, version, owner FROM tasks WHERE id = $1",
+		int64(id),
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, taskNotFound(id)
+	}
+	if err != nil {
+		return nil, postgresError(err)
+	}
+	return task, nil
+}
+
+// getTasks calls f for every task selected by q, in q.order.
+//
+// The tasks are read postgresBatchSize at a time and f is called once
+// the batch is read, so a slow f doesn't hold a pooled connection for
+// the whole listing. Each batch resumes after the last task of the
+// previous one, so tasks added or updated during the iteration will be
+// visible to f if they sort after it.
+func (d *postgresDB) getTasks(ctx context.Context, q taskQuery, f func(any) error) error {
+	key, dir := postgresOrderKey(q.order.field), "ASC"
+	if q.order.desc {
+		dir = "DESC"
+	}
+	remaining := q.limit
+	for {
+		size := postgresBatchSize
+		if q.limit > 0 && remaining < size {
+			size = remaining
+		}
+		where, args := postgresWhere(q, []any{size})
+		rows, err := d.pool.Query(ctx,
+			"SELECT id, description, done, due_date, version, owner FROM tasks"+where+
+				" ORDER BY "+key+" "+dir+", id "+dir+" LIMIT $1",
+			args...,
+		)
+		if err != nil {
+			return postgresError(err)
+		}
+		batch, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*pb.Task, error) {
+			return scanTask(row)
+		})
+		if err != nil {
+			return postgresError(err)
+		}
+
+		for _, task := range batch {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := f(task); err != nil {
+				return err
+			}
+		}
+		remaining -= len(batch)
+		if len(batch) < size || (q.limit > 0 && remaining == 0) {
+			return nil
+		}
+		q.after = batch[len(batch)-1]
+	}
+}
+
+// postgresOrderKey returns the expression tasks are sorted by for
+// field. Descriptions are compared byte by byte whatever the collation
+// of the database, and tasks without due date come after all others.
+func postgresOrderKey(field orderField) string {
+	switch field {
+	case orderByDueDate:
+		return "COALESCE(due_date, 'infinity')"
+	case orderByDescription:
+		return `description COLLATE "C"`
+	case orderByDone:
+		return "done"
+	default:
+		return "id"
+	}
+}
+
+// postgresWhere returns the WHERE clause, with a leading space,
+// selecting the tasks of q. Its arguments are appended to args, the
+// placeholders are numbered accordingly. Tasks without due date have a
+// NULL due_date, which never matches the date criteria of the filter.
+func postgresWhere(q taskQuery, args []any) (string, []any) {
+	var conds []string
+	// cond appends a condition, its %d are replaced by the placeholders
+	// of arg, in order.
+	cond := func(format string, arg ...any) {
+		placeholders := make([]any, len(arg))
+		for i := range arg {
+			placeholders[i] = len(args) + i + 1
+		}
+		conds = append(conds, fmt.Sprintf(format, placeholders...))
+		args = append(args, arg...)
+	}
+
+	cond("owner = $%d", q.owner)
+	if after := q.after; after != nil {
+		op := ">"
+		if q.order.desc {
+			op = "<"
+		}
+		switch q.order.field {
+		case orderByID:
+			cond("id "+op+" $%d", int64(after.Id))
+		case orderByDueDate:
+			var dueDate *time.Time
+			if after.DueDate != nil {
+				t := after.DueDate.AsTime()
+				dueDate = &t
+			}
+			cond("(COALESCE(due_date, 'infinity'), id) "+op+" (COALESCE($%d::timestamptz, 'infinity'), $%d)", dueDate, int64(after.Id))
+		case orderByDescription:
+			cond(`(description COLLATE "C", id) `+op+` ($%d::text COLLATE "C", $%d)`, after.Description, int64(after.Id))
+		case orderByDone:
+			cond("(done, id) "+op+" ($%d, $%d)", after.Done, int64(after.Id))
+		}
+	}
+
+	f := q.filter
+	if f.done != nil {
+		cond("done = $%d", *f.done)
+	}
+	if !f.overdueAt.IsZero() {
+		cond("NOT done AND due_date < $%d", f.overdueAt)
+	}
+	if !f.dueAfter.IsZero() {
+		cond("due_date >= $%d", f.dueAfter)
+	}
+	if !f.dueBefore.IsZero() {
+		cond("due_date < $%d", f.dueBefore)
+	}
+	if f.description != "" {
+		// strpos is case-sensitive, unlike ILIKE
+		cond("strpos(description, $%d) > 0", f.description)
+	}
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func (d *postgresDB) updateTask(ctx context.Context, id uint64, update *pb.Task, mask *fieldmaskpb.FieldMask, pre precondition) error {
+	return d.write(ctx, func(tx pgx.Tx) error {
+		task, err := scanTask(tx.QueryRow(ctx,
+			"SELECT id, description, done, due_date, version, owner FROM tasks WHERE id = $1 FOR UPDATE",
+			int64(id),
+		))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return taskNotFound(id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := pre.check(task); err != nil {
+			return err
+		}
+
+		applyUpdate(task, update, mask)
+		var dueDate *time.Time
+		if task.DueDate != nil {
+			t := task.DueDate.AsTime()
+			dueDate = &t
+		}
+		// the due date is returned as stored, in microseconds
+		err = tx.QueryRow(ctx,
+			"UPDATE tasks SET description = $2, done = $3, due_date = $4, version = $5 WHERE id = $1 RETURNING due_date",
+			int64(id), task.Description, task.Done, dueDate, int64(task.Version),
+		).Scan(&dueDate)
+		if err != nil {
+			return err
+		}
+		if dueDate != nil {
+			task.DueDate = timestamppb.New(*dueDate)
+		}
+		return recordPostgresEvent(ctx, tx, eventUpdated, task)
+	})
+}
+
+// deleteTask rolls the deletion back if the task doesn't have the
+// precondition.
+func (d *postgresDB) deleteTask(ctx context.Context, id uint64, pre precondition) error {
+	return d.write(ctx, func(tx pgx.Tx) error {
+		task, err := scanTask(tx.QueryRow(ctx,
+			"DELETE FROM tasks WHERE id = $1 RETURNING id, description, done, due_date, version, owner",
+			int64(id),
+		))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return taskNotFound(id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := pre.check(task); err != nil {
+			return err
+		}
+		return recordPostgresEvent(ctx, tx, eventDeleted, task)
+	})
+}
+
+// watchTasks also polls the database, to see the changes written by
+// the other replicas.
+func (d *postgresDB) watchTasks(ctx context.Context, since uint64, f func(taskEvent) error) error {
+	return watchChanges(ctx, d, &d.changes, watchPollInterval, since, f)
+}
+
+func (d *postgresDB) lastRevision(ctx context.Context) (uint64, error) {
+	var rev int64
+	err := d.pool.QueryRow(ctx, "SELECT COALESCE(MAX(revision), 0) FROM task_events").Scan(&rev)
+	return uint64(rev), postgresError(err)
+}
+
+func (d *postgresDB) eventsAfter(ctx context.Context, rev uint64) ([]taskEvent, error) {
+	rows, err := d.pool.Query(ctx,
+		"SELECT revision, kind, task FROM task_events WHERE revision > $1 ORDER BY revision LIMIT $2",
+		int64(rev), eventBatchSize,
+	)
+	if err != nil {
+		return nil, postgresError(err)
+	}
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (taskEvent, error) {
+		var (
+			e        taskEvent
+			revision int64
+			kind     int16
+			task     []byte
+		)
+		if err := row.Scan(&revision, &kind, &task); err != nil {
+			return e, err
+		}
+		e.revision, e.kind, e.task = uint64(revision), eventKind(kind), &pb.Task{}
+		return e, proto.Unmarshal(task, e.task)
+	})
+	return events, postgresError(err)
+}
+
+// postgresError wraps the errors caused by concurrent transactions
+// with errConflict and the connection errors with errUnavailable,
+// other errors are returned as is.
+func postgresError(err error) error {
+	if err == nil || errors.Is(err, errNotFound) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "40001" || pgErr.Code == "40P01":
+			// serialization_failure, deadlock_detected
+			return fmt.Errorf("%w: %w", errConflict, err)
+		case strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "57P"):
+			// connection_exception, operator_intervention (e.g. shutdown)
+			return fmt.Errorf("%w: %w", errUnavailable, err)
+		default:
+			return err
+		}
+	}
+
+	var netErr net.Error
+	if pgconn.SafeToRetry(err) || pgconn.Timeout(err) || errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %w", errUnavailable, err)
+	}
+	return err
+}
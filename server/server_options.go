@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/time/rate"
+)
+
+type serverOptions struct {
+	// now is the clock tasks are checked against to be overdue, and
+	// ListTasks calls are paced with.
+	now    func() time.Time
+	logger *slog.Logger
+	// listRate and listBurst pace the tasks sent by each ListTasks
+	// call, an infinite rate sends them as fast as the client reads.
+	listRate  rate.Limit
+	listBurst int
+	// sleep waits for d to pass on the clock, or for ctx to be done.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+var defaultServerOptions = serverOptions{
+	now:       time.Now,
+	logger:    slog.Default(),
+	listRate:  rate.Inf,
+	listBurst: 1,
+	sleep:     sleep,
+}
+
+// sleep waits for d, or returns the error of ctx if it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type ServerOption interface {
+	apply(*serverOptions)
+}
+
+type funcServerOption struct {
+	f func(*serverOptions)
+}
+
+func (fso *funcServerOption) apply(so *serverOptions) {
+	fso.f(so)
+}
+
+func newFuncServerOption(f func(*serverOptions)) *funcServerOption {
+	return &funcServerOption{
+		f: f,
+	}
+}
+
+// ListPacing limits every ListTasks call to r tasks per second, after
+// an initial burst of up to burst tasks. It spreads the load of large
+// listings over time, at the expense of their latency. A burst lower
+// than 1 is treated as 1.
+func ListPacing(r rate.Limit, burst int) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		if burst < 1 {
+			burst = 1
+		}
+		o.listRate = r
+		o.listBurst = burst
+	})
+}
+
+// Clock replaces time.Now as the time tasks are checked against to be
+// overdue, and ListTasks calls are paced with.
+func Clock(now func() time.Time) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.now = now
+	})
+}
+
+// Logger sets the logger of the requests handlers, slog.Default() is
+// used otherwise.
+func Logger(l *slog.Logger) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.logger = l
+	})
+}
@@ -2,16 +2,23 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
+	grpcauth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
 	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"golang.org/x/exp/slices"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -25,10 +32,7 @@ var (
 func init() {
 	lis = bufconn.Listen(bufSize)
 	s := grpc.NewServer()
-	var testServer *server = &server{
-		d: fakeDB,
-	}
-	pb.RegisterTodoServiceServer(s, testServer)
+	pb.RegisterTodoServiceServer(s, newServer(fakeDB))
 	go func() {
 		if err := s.Serve(lis); err != nil && err.Error() != "closed" {
 			log.Fatalf("server exited with error: %v\n", err)
@@ -48,20 +52,37 @@ func TestRunAll(t *testing.T) {
 	t.Run("AddTaskTests", func(t *testing.T) {
 		t.Run("TestAddTaskEmptyDescription", testAddTaskEmptyDescription)
 		t.Run("TestAddTaskUnavailableDb", testAddTaskUnavailableDb)
+		t.Run("TestAddTaskSlowDb", testAddTaskSlowDb)
+		t.Run("TestAddTasks", testAddTasks)
+		t.Run("TestAddTasksInvalid", testAddTasksInvalid)
 	})
 
+	t.Run("GetTask", testGetTask)
+
 	t.Run("ListTasks", func(t *testing.T) {
 		t.Run("TestListTasks", testListTasks)
+		t.Run("TestListTasksSlowDb", testListTasksSlowDb)
+		t.Run("TestListTasksMask", testListTasksMask)
+		t.Run("TestListTasksFilter", testListTasksFilter)
+		t.Run("TestListTasksPages", testListTasksPages)
+		t.Run("TestListTasksOrder", testListTasksOrder)
+		t.Run("TestListTasksPacing", testListTasksPacing)
+		t.Run("TestListTasksOverdue", testListTasksOverdue)
 	})
 
 	t.Run("UpdateTasks", testUpdateTasks)
-	t.Run("DeleteTasks", testUpdateTasks)
+	t.Run("UpdateTasksResults", testUpdateTasksResults)
+	t.Run("UpdateTasksMask", testUpdateTasksMask)
+	t.Run("DeleteTasks", testDeleteTasks)
+	t.Run("DeleteTasksResults", testDeleteTasksResults)
+	t.Run("WatchTasks", testWatchTasks)
+	t.Run("Owners", testOwners)
 
 }
 
 const (
 	errorInvalidDescription = "invalid AddTaskRequest.Description: value length must be at least 1 runes"
-	errorNoDatabaseAccess   = "unexpected error: couldn't access the database"
+	errorNoDatabaseAccess   = "couldn't access the database"
 )
 
 func testAddTaskEmptyDescription(t *testing.T) {
@@ -88,18 +109,133 @@ func testAddTaskUnavailableDb(t *testing.T) {
 	}
 	_, err := c.AddTask(context.TODO(), req)
 	fakeDB.Reset()
-	if !errorIs(err, codes.Internal, errorNoDatabaseAccess) {
-		t.Errorf("expected Internal, got %v", err)
+	if !errorIs(err, codes.Unavailable, errorNoDatabaseAccess) {
+		t.Errorf("expected Unavailable, got %v", err)
+	}
+}
+
+func testAddTaskSlowDb(t *testing.T) {
+	conn, c := newClient(t)
+	defer conn.Close()
+	newDb := NewFakeDb(Delay(5 * time.Second))
+	*fakeDB = *newDb
+	defer fakeDB.Reset()
+	req := &pb.AddTaskRequest{
+		Description: "test",
+		DueDate:     timestamppb.New(time.Now().Add(5 * time.Hour)),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := c.AddTask(ctx, req)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+	// the deadline is propagated to the database call, which should
+	// give up long before the end of its delay.
+	waitCancelled(t, 1)
+}
+
+func testGetTask(t *testing.T) {
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	d := New()
+	id, err := d.addTask(context.TODO(), "", "test", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := startServer(t, d, Clock(func() time.Time { return now }))
+
+	t.Run("Mask", func(t *testing.T) {
+		// the mask doesn't keep the fields overdue depends on
+		res, err := c.GetTask(context.TODO(), &pb.GetTaskRequest{
+			Id:   id,
+			Mask: &fieldmaskpb.FieldMask{Paths: []string{"id", "description"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Task.Id != id || res.Task.Description != "test" || res.Task.DueDate != nil {
+			t.Errorf("expected only id and description, got %v", res.Task)
+		}
+		if !res.Overdue {
+			t.Error("expected task to be overdue")
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := c.GetTask(context.TODO(), &pb.GetTaskRequest{Id: id + 1})
+		if code := status.Code(err); code != codes.NotFound {
+			t.Errorf("expected %v, got %v", codes.NotFound, err)
+		}
+	})
+
+	t.Run("InvalidMask", func(t *testing.T) {
+		_, err := c.GetTask(context.TODO(), &pb.GetTaskRequest{
+			Id:   id,
+			Mask: &fieldmaskpb.FieldMask{Paths: []string{"unknown"}},
+		})
+		if code := status.Code(err); code != codes.InvalidArgument {
+			t.Errorf("expected %v, got %v", codes.InvalidArgument, err)
+		}
+	})
+}
+
+func testAddTasks(t *testing.T) {
+	c := startServer(t, New())
+	dueDate := timestamppb.New(time.Now().Add(5 * time.Hour))
+	res, err := c.AddTasks(context.TODO(), &pb.AddTasksRequest{
+		Tasks: []*pb.AddTaskRequest{
+			{Description: "first", DueDate: dueDate},
+			{Description: "second", DueDate: dueDate},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(res.Ids, []uint64{1, 2}) {
+		t.Errorf("expected ids [1 2], got %v", res.Ids)
+	}
+}
+
+func testAddTasksInvalid(t *testing.T) {
+	d := New()
+	c := startServer(t, d)
+	dueDate := timestamppb.New(time.Now().Add(5 * time.Hour))
+	tests := []struct {
+		name  string
+		tasks []*pb.AddTaskRequest
+	}{
+		{"Empty", nil},
+		{"Description", []*pb.AddTaskRequest{
+			{Description: "valid", DueDate: dueDate},
+			{DueDate: dueDate},
+		}},
+		{"DueDate", []*pb.AddTaskRequest{
+			{Description: "valid", DueDate: dueDate},
+			{Description: "past", DueDate: timestamppb.New(time.Now().Add(-time.Hour))},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.AddTasks(context.TODO(), &pb.AddTasksRequest{Tasks: tt.tasks})
+			if code := status.Code(err); code != codes.InvalidArgument {
+				t.Errorf("expected %v, got %v", codes.InvalidArgument, err)
+			}
+		})
+	}
+	// the valid tasks of the batches are not added either
+	if tasks := listTasks(t, d); len(tasks) != 0 {
+		t.Errorf("expected no task, got %v", tasks)
 	}
 }
 
 func testListTasks(t *testing.T) {
 	conn, c := newClient(t)
 	defer conn.Close()
-	fakeDB.d.tasks = []*pb.Task{
+	tasks := []*pb.Task{
 		{}, {}, {}, // 3 empty tasks
 	}
-	expectedRead := len(fakeDB.d.tasks)
+	seedTasks(t, tasks...)
+	expectedRead := len(tasks)
 	req := &pb.ListTasksRequest{}
 	count := 0
 	res, err := c.ListTasks(context.TODO(), req)
@@ -121,16 +257,317 @@ func testListTasks(t *testing.T) {
 	}
 }
 
-func testUpdateTasks(t *testing.T) {
+func testListTasksSlowDb(t *testing.T) {
+	conn, c := newClient(t)
+	defer conn.Close()
+	newDb := NewFakeDb(Delay(5 * time.Second))
+	*fakeDB = *newDb
+	defer fakeDB.Reset()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	res, err := c.ListTasks(ctx, &pb.ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := res.Recv(); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+	waitCancelled(t, 1)
+}
+
+func testListTasksMask(t *testing.T) {
 	conn, c := newClient(t)
 	defer conn.Close()
-	fakeDB.d.tasks = []*pb.Task{
-		{Id: 0, Description: "test1"},
-		{Id: 1, Description: "test2"},
-		{Id: 2, Description: "test3"},
+	seedTasks(t, &pb.Task{
+		Description: "test",
+		DueDate:     &timestamppb.Timestamp{Seconds: 10, Nanos: 20},
+	})
+
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"id", "due_date.seconds"}}
+	res, err := c.ListTasks(context.TODO(), &pb.ListTasksRequest{Mask: mask})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
+	got, err := res.Recv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := got.Task
+	if task.Id != 1 || task.Description != "" || task.DueDate.GetSeconds() != 10 || task.DueDate.GetNanos() != 0 {
+		t.Errorf("expected only id and due_date.seconds, got %v", task)
+	}
+
+	mask = &fieldmaskpb.FieldMask{Paths: []string{"due_date.minutes"}}
+	res, err = c.ListTasks(context.TODO(), &pb.ListTasksRequest{Mask: mask})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = res.Recv()
+	if !errorIs(err, codes.InvalidArgument, `invalid mask: unknown Timestamp field "minutes"`) {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func testListTasksFilter(t *testing.T) {
+	conn, c := newClient(t)
+	defer conn.Close()
+	seedTasks(t,
+		&pb.Task{Description: "overdue", DueDate: timestamppb.New(time.Now().Add(-time.Hour))},
+		&pb.Task{Description: "done", Done: true, DueDate: timestamppb.New(time.Now().Add(-time.Hour))},
+		&pb.Task{Description: "later", DueDate: timestamppb.New(time.Now().Add(time.Hour))},
+	)
+
+	req := &pb.ListTasksRequest{Filter: &pb.TaskFilter{OverdueOnly: true}}
+	res, err := c.ListTasks(context.TODO(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []uint64
+	for {
+		got, err := res.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, got.Task.Id)
+	}
+	if len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("expected only task 1, got %v", ids)
+	}
+
+	now := timestamppb.Now()
+	req = &pb.ListTasksRequest{Filter: &pb.TaskFilter{DueAfter: now, DueBefore: now}}
+	res, err = c.ListTasks(context.TODO(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = res.Recv()
+	if !errorIs(err, codes.InvalidArgument, "invalid filter: due_after should be before due_before") {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+// listPage returns the ids of the tasks of a page and its next page token.
+func listPage(t *testing.T, c pb.TodoServiceClient, req *pb.ListTasksRequest) ([]uint64, string) {
+	t.Helper()
+	res, err := c.ListTasks(context.TODO(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var (
+		ids  []uint64
+		next string
+	)
+	for {
+		got, err := res.Recv()
+		if err == io.EOF {
+			return ids, next
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, got.Task.Id)
+		next = got.NextPageToken
+	}
+}
+
+func testListTasksPages(t *testing.T) {
+	conn, c := newClient(t)
+	defer conn.Close()
+	seedTasks(t, &pb.Task{}, &pb.Task{}, &pb.Task{}, &pb.Task{}, &pb.Task{})
+
+	ids, next := listPage(t, c, &pb.ListTasksRequest{PageSize: 2})
+	if !slices.Equal(ids, []uint64{1, 2}) || next == "" {
+		t.Fatalf("expected tasks 1 and 2 and a next page, got %v %q", ids, next)
+	}
+
+	// the token stays valid when the last task listed is deleted and
+	// new tasks are added
+	if err := fakeDB.d.deleteTask(context.TODO(), 2, precondition{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fakeDB.d.addTask(context.TODO(), "", "new", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	ids, next = listPage(t, c, &pb.ListTasksRequest{PageSize: 2, PageToken: next})
+	if !slices.Equal(ids, []uint64{3, 4}) || next == "" {
+		t.Fatalf("expected tasks 3 and 4 and a next page, got %v %q", ids, next)
+	}
+	ids, next = listPage(t, c, &pb.ListTasksRequest{PageSize: 2, PageToken: next})
+	if !slices.Equal(ids, []uint64{5, 6}) || next != "" {
+		t.Fatalf("expected tasks 5 and 6 and no next page, got %v %q", ids, next)
+	}
+
+	// every task carries the token resuming after it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	res, err := c.ListTasks(ctx, &pb.ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first, err := res.Recv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids, _ = listPage(t, c, &pb.ListTasksRequest{PageSize: 1, PageToken: first.NextPageToken})
+	if !slices.Equal(ids, []uint64{3}) {
+		t.Errorf("expected task 3 after task 1, got %v", ids)
+	}
+
+	res, err = c.ListTasks(context.TODO(), &pb.ListTasksRequest{PageToken: "invalid"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := res.Recv(); !errorIs(err, codes.InvalidArgument, "invalid page_token: malformed token") {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func testListTasksOrder(t *testing.T) {
+	conn, c := newClient(t)
+	defer conn.Close()
+	now := time.Now()
+	seedTasks(t,
+		&pb.Task{Description: "b", DueDate: timestamppb.New(now.Add(2 * time.Hour))},
+		&pb.Task{Description: "a", DueDate: timestamppb.New(now.Add(3 * time.Hour))},
+		&pb.Task{Description: "c", DueDate: timestamppb.New(now.Add(time.Hour))},
+	)
+
+	order := &pb.TaskOrder{Field: pb.TaskOrder_FIELD_DUE_DATE, Descending: true}
+	ids, next := listPage(t, c, &pb.ListTasksRequest{OrderBy: order, PageSize: 2})
+	if !slices.Equal(ids, []uint64{2, 1}) || next == "" {
+		t.Fatalf("expected tasks 2 and 1 and a next page, got %v %q", ids, next)
+	}
+	ids, next = listPage(t, c, &pb.ListTasksRequest{OrderBy: order, PageSize: 2, PageToken: next})
+	if !slices.Equal(ids, []uint64{3}) || next != "" {
+		t.Fatalf("expected task 3 and no next page, got %v %q", ids, next)
+	}
+
+	// tokens are only valid in the order of their listing
+	ids, next = listPage(t, c, &pb.ListTasksRequest{OrderBy: order, PageSize: 1})
+	if !slices.Equal(ids, []uint64{2}) || next == "" {
+		t.Fatalf("expected task 2 and a next page, got %v %q", ids, next)
+	}
+	order = &pb.TaskOrder{Field: pb.TaskOrder_FIELD_DESCRIPTION}
+	res, err := c.ListTasks(context.TODO(), &pb.ListTasksRequest{OrderBy: order, PageToken: next})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := res.Recv(); !errorIs(err, codes.InvalidArgument, "invalid page_token: listing in another order_by") {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func testListTasksPacing(t *testing.T) {
+	d := New()
+	for i := 0; i < 6; i++ {
+		if _, err := d.addTask(context.TODO(), "", "test", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the clock only moves when the server sleeps
+	var (
+		mu     sync.Mutex
+		now    = time.Now()
+		sleeps []time.Duration
+	)
+	clock := Clock(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	})
+	fakeSleep := newFuncServerOption(func(o *serverOptions) {
+		o.sleep = func(_ context.Context, d time.Duration) error {
+			mu.Lock()
+			defer mu.Unlock()
+			now = now.Add(d)
+			sleeps = append(sleeps, d)
+			return nil
+		}
+	})
+
+	// 2 tasks right away, then one every 20ms
+	c := startServer(t, d, ListPacing(50, 2), clock, fakeSleep)
+	ids, _ := listPage(t, c, &pb.ListTasksRequest{})
+	if len(ids) != 6 {
+		t.Fatalf("expected 6 tasks, got %v", ids)
+	}
+	mu.Lock()
+	expected := []time.Duration{20 * time.Millisecond, 20 * time.Millisecond, 20 * time.Millisecond, 20 * time.Millisecond}
+	if !slices.Equal(sleeps, expected) {
+		t.Errorf("expected to sleep %v, slept %v", expected, sleeps)
+	}
+	sleeps = nil
+	mu.Unlock()
+
+	// the listing fails as soon as the deadline can't be met, the next
+	// task would be sent in a second
+	c = startServer(t, d, ListPacing(1, 1), clock, fakeSleep)
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	res, err := c.ListTasks(ctx, &pb.ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for err == nil {
+		_, err = res.Recv()
+	}
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sleeps) != 0 {
+		t.Errorf("expected not to sleep, slept %v", sleeps)
+	}
+}
+
+func testListTasksOverdue(t *testing.T) {
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	d := New()
+	for _, dueDate := range []time.Time{now.Add(-time.Hour), now.Add(time.Hour), now.Add(-time.Hour)} {
+		if _, err := d.addTask(context.TODO(), "", "test", dueDate); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.updateTask(context.TODO(), 3, &pb.Task{Done: true}, &fieldmaskpb.FieldMask{Paths: []string{"done"}}, precondition{}); err != nil {
+		t.Fatal(err)
+	}
+	c := startServer(t, d, Clock(func() time.Time { return now }))
+
+	// the mask doesn't keep the fields overdue depends on
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"id"}}
+	res, err := c.ListTasks(context.TODO(), &pb.ListTasksRequest{Mask: mask})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[uint64]bool{1: true, 2: false, 3: false}
+	for {
+		got, err := res.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Overdue != expected[got.Task.Id] {
+			t.Errorf("expected task %d overdue to be %t", got.Task.Id, expected[got.Task.Id])
+		}
+	}
+}
+
+func testUpdateTasks(t *testing.T) {
+	conn, c := newClient(t)
+	defer conn.Close()
+	seedTasks(t, []*pb.Task{
+		{Description: "test1"},
+		{Description: "test2"},
+		{Description: "test3"},
+	}...)
 	requests := []*pb.UpdateTasksRequest{
-		{Id: 0}, {Id: 1}, {Id: 2},
+		{Id: 1}, {Id: 2}, {Id: 3},
 	}
 	expectedUpdates := len(requests)
 	stream, err := c.UpdateTasks(context.TODO())
@@ -153,13 +590,103 @@ func testUpdateTasks(t *testing.T) {
 	}
 }
 
+func testUpdateTasksResults(t *testing.T) {
+	conn, c := newClient(t)
+	defer conn.Close()
+	seedTasks(t, &pb.Task{Description: "test1"}, &pb.Task{Description: "test2"})
+	requests := []*pb.UpdateTasksRequest{
+		{Id: 1, Description: "updated1"},
+		{Id: 42, Description: "missing"},
+		{Id: 2, Description: "updated2", ExpectedVersion: 1},
+		{Id: 43, Description: "missing"},
+		{Id: 2, Description: "stale", ExpectedVersion: 1},
+	}
+	stream, err := c.UpdateTasks(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Applied != 2 || res.Failed != 3 {
+		t.Errorf("expected 2 applied and 3 failed updates, got %v", res)
+	}
+	if len(res.Failures) != 3 {
+		t.Fatalf("expected 3 failures, got %v", res.Failures)
+	}
+	for i, id := range []uint64{42, 43} {
+		f := res.Failures[i]
+		reason := fmt.Sprintf("task with id %d not found", id)
+		if f.Id != id || f.Reason != reason || codes.Code(f.Code) != codes.NotFound {
+			t.Errorf("expected failure %q for task %d, got %v", reason, id, f)
+		}
+	}
+	if f := res.Failures[2]; f.Id != 2 || codes.Code(f.Code) != codes.Aborted {
+		t.Errorf("expected %v for stale update of task 2, got %v", codes.Aborted, f)
+	}
+	if task := listTasks(t, fakeDB)[1]; task.Description != "updated2" || task.Version != 2 {
+		t.Errorf("expected only the first update of task 2, got %v", task)
+	}
+}
+
+func testUpdateTasksMask(t *testing.T) {
+	conn, c := newClient(t)
+	defer conn.Close()
+	seedTasks(t, &pb.Task{
+		Description: "test",
+		DueDate:     timestamppb.New(time.Now().Add(time.Hour)),
+	})
+	requests := []*pb.UpdateTasksRequest{
+		{Id: 1, Done: true, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"done"}}},
+		{Id: 1, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"priority"}}},
+		{Id: 1, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"id"}}},
+	}
+	stream, err := c.UpdateTasks(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Applied != 1 || res.Failed != 2 {
+		t.Errorf("expected 1 applied and 2 failed updates, got %v", res)
+	}
+	reasons := []string{
+		`invalid update_mask: unknown Task field "priority"`,
+		`invalid update_mask: field "id" of Task cannot be updated`,
+	}
+	for i, f := range res.Failures {
+		if f.Reason != reasons[i] {
+			t.Errorf("expected reason %q, got %q", reasons[i], f.Reason)
+		}
+	}
+
+	task := listTasks(t, fakeDB)[0]
+	if !task.Done || task.Description != "test" || task.DueDate == nil {
+		t.Errorf("expected only done to be updated, got %v", task)
+	}
+}
+
 func testDeleteTasks(t *testing.T) {
 	conn, c := newClient(t)
 	defer conn.Close()
-	fakeDB.d.tasks = []*pb.Task{
+	tasks := []*pb.Task{
 		{Id: 1}, {Id: 2}, {Id: 3},
 	}
-	expectedRead := len(fakeDB.d.tasks)
+	seedTasks(t, tasks...)
+	expectedRead := len(tasks)
 	waitc := make(chan countAndError)
 	requests := []*pb.DeleteTasksRequest{
 		{Id: 1}, {Id: 2}, {Id: 3},
@@ -179,6 +706,46 @@ func testDeleteTasks(t *testing.T) {
 	}
 }
 
+func testDeleteTasksResults(t *testing.T) {
+	conn, c := newClient(t)
+	defer conn.Close()
+	seedTasks(t, &pb.Task{}, &pb.Task{})
+	requests := []*pb.DeleteTasksRequest{
+		{Id: 2, ExpectedVersion: 2}, {Id: 2}, {Id: 42}, {Id: 2},
+	}
+	expected := []pb.DeleteTasksResponse_Status{
+		pb.DeleteTasksResponse_STATUS_VERSION_MISMATCH,
+		pb.DeleteTasksResponse_STATUS_DELETED,
+		pb.DeleteTasksResponse_STATUS_NOT_FOUND,
+		pb.DeleteTasksResponse_STATUS_NOT_FOUND,
+	}
+	stream, err := c.DeleteTasks(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Id != req.Id || res.Status != expected[i] {
+			t.Errorf("expected %v for task %d, got %v", expected[i], req.Id, res)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+	if tasks := listTasks(t, fakeDB); len(tasks) != 1 || tasks[0].Id != 1 {
+		t.Errorf("expected only task 1 left, got %v", tasks)
+	}
+}
+
 type countAndError struct {
 	count int
 	err   error
@@ -216,3 +783,215 @@ func readResponsesOverStream(stream pb.TodoService_DeleteTasksClient, waitc chan
 	waitc <- countAndError{count: count}
 	close(waitc)
 }
+
+func testWatchTasks(t *testing.T) {
+	d := New()
+	c := startServer(t, d)
+	ids := addTasks(t, d, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := c.WatchTasks(ctx, &pb.WatchTasksRequest{SinceRevision: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.updateTask(context.TODO(), ids[0], &pb.Task{Done: true}, &fieldmaskpb.FieldMask{Paths: []string{"done"}}, precondition{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.deleteTask(context.TODO(), ids[0], precondition{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*pb.WatchTasksResponse{
+		{Type: pb.WatchTasksResponse_TYPE_UPDATED, Revision: 2},
+		{Type: pb.WatchTasksResponse_TYPE_DELETED, Revision: 3},
+	}
+	for _, e := range expected {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Type != e.Type || res.Revision != e.Revision || res.Task.Id != ids[0] || !res.Task.Done {
+			t.Errorf("expected %v of task %d, got %v", e, ids[0], res)
+		}
+	}
+
+	// resuming from a revision not kept anymore
+	addTasks(t, d, 2*eventRetention)
+	stream, err = c.WatchTasks(ctx, &pb.WatchTasksRequest{SinceRevision: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.OutOfRange {
+		t.Errorf("expected OutOfRange, got %v", err)
+	}
+}
+
+// testOwners checks that the callers only see, and change, the tasks
+// they added.
+func testOwners(t *testing.T) {
+	// the token is the subject
+	a := AuthenticatorFunc(func(_ context.Context, token string) (*Principal, error) {
+		return &Principal{Subject: token}, nil
+	})
+	c := startGrpcServer(t, []grpc.ServerOption{
+		grpc.UnaryInterceptor(grpcauth.UnaryServerInterceptor(authFunc(a))),
+		grpc.StreamInterceptor(grpcauth.StreamServerInterceptor(authFunc(a))),
+	}, New())
+	as := func(subject string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), authTokenKey, subject)
+	}
+	add := func(subject, description string) uint64 {
+		t.Helper()
+		res, err := c.AddTask(as(subject), &pb.AddTaskRequest{
+			Description: description,
+			DueDate:     timestamppb.New(time.Now().Add(time.Hour)),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return res.Id
+	}
+	alice := add("alice", "alice's task")
+	bob := add("bob", "bob's task")
+
+	t.Run("GetTask", func(t *testing.T) {
+		res, err := c.GetTask(as("alice"), &pb.GetTaskRequest{Id: alice})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Task.Owner != "alice" {
+			t.Errorf("expected owner alice, got %q", res.Task.Owner)
+		}
+		if _, err := c.GetTask(as("alice"), &pb.GetTaskRequest{Id: bob}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("expected %v, got %v", codes.PermissionDenied, err)
+		}
+	})
+
+	t.Run("ListTasks", func(t *testing.T) {
+		for subject, id := range map[string]uint64{"alice": alice, "bob": bob} {
+			stream, err := c.ListTasks(as(subject), &pb.ListTasksRequest{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []uint64
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				got = append(got, res.Task.Id)
+			}
+			if !slices.Equal(got, []uint64{id}) {
+				t.Errorf("expected %s to list [%d], got %v", subject, id, got)
+			}
+		}
+	})
+
+	t.Run("UpdateTasks", func(t *testing.T) {
+		stream, err := c.UpdateTasks(as("alice"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := stream.Send(&pb.UpdateTasksRequest{Id: bob, Done: true, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"done"}}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		res, err := stream.CloseAndRecv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Failed != 1 || codes.Code(res.Failures[0].Code) != codes.PermissionDenied {
+			t.Errorf("expected a %v failure, got %v", codes.PermissionDenied, res)
+		}
+	})
+
+	t.Run("DeleteTasks", func(t *testing.T) {
+		stream, err := c.DeleteTasks(as("alice"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := map[uint64]pb.DeleteTasksResponse_Status{
+			bob:   pb.DeleteTasksResponse_STATUS_PERMISSION_DENIED,
+			alice: pb.DeleteTasksResponse_STATUS_DELETED,
+		}
+		for _, id := range []uint64{bob, alice} {
+			if err := stream.Send(&pb.DeleteTasksRequest{Id: id}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			res, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.Status != expected[id] {
+				t.Errorf("expected %v deleting task %d, got %v", expected[id], id, res.Status)
+			}
+		}
+		stream.CloseSend()
+		// untouched by alice
+		res, err := c.GetTask(as("bob"), &pb.GetTaskRequest{Id: bob})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Task.Done || res.Task.Version != 1 {
+			t.Errorf("expected bob's task to be left untouched, got %v", res.Task)
+		}
+	})
+
+	t.Run("WatchTasks", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(as("alice"))
+		defer cancel()
+		// after the creation of alice's task
+		stream, err := c.WatchTasks(ctx, &pb.WatchTasksRequest{SinceRevision: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Type != pb.WatchTasksResponse_TYPE_DELETED || res.Task.Id != alice {
+			t.Errorf("expected the deletion of task %d, got %v", alice, res)
+		}
+	})
+}
+
+func BenchmarkListTasks(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("Tasks%d", n), func(b *testing.B) {
+			d := New()
+			for i := 0; i < n; i++ {
+				if _, err := d.addTask(context.TODO(), "", fmt.Sprintf("task %d", i), time.Now()); err != nil {
+					b.Fatal(err)
+				}
+			}
+			c := startServer(b, d)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				res, err := c.ListTasks(context.TODO(), &pb.ListTasksRequest{})
+				if err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+				count := 0
+				for {
+					_, err := res.Recv()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						b.Fatalf("unexpected error: %v", err)
+					}
+					count++
+				}
+				if count != n {
+					b.Fatalf("expected %d tasks, got %d", n, count)
+				}
+			}
+			b.ReportMetric(float64(n*b.N)/b.Elapsed().Seconds(), "tasks/s")
+		})
+	}
+}
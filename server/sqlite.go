@@ -0,0 +1,480 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteBatchSize is the number of tasks getTasks reads per query.
+const sqliteBatchSize = 64
+
+// sqliteMigrations are applied in order on startup. The number of
+// applied migrations is stored in the user_version pragma, so
+// migrations must never be edited or reordered, only appended.
+var sqliteMigrations = []string{
+	// AUTOINCREMENT makes sure ids of deleted tasks are never reused.
+	`CREATE TABLE tasks (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		description TEXT    NOT NULL,
+		done        INTEGER NOT NULL DEFAULT 0,
+		due_date    INTEGER NOT NULL -- unix nanoseconds
+	)`,
+	// tasks can be updated to have no due date. SQLite can't drop a
+	// NOT NULL constraint, the table is rebuilt keeping its sequence.
+	`CREATE TABLE tasks_new (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		description TEXT    NOT NULL,
+		done        INTEGER NOT NULL DEFAULT 0,
+		due_date    INTEGER -- unix nanoseconds
+	);
+	INSERT INTO tasks_new (id, description, done, due_date)
+		SELECT id, description, done, due_date FROM tasks;
+	DELETE FROM sqlite_sequence WHERE name = 'tasks_new';
+	INSERT INTO sqlite_sequence (name, seq)
+		SELECT 'tasks_new', seq FROM sqlite_sequence WHERE name = 'tasks';
+	DROP TABLE tasks;
+	ALTER TABLE tasks_new RENAME TO tasks`,
+	// change feed of WatchTasks, revisions are never reused.
+	`CREATE TABLE task_events (
+		revision INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind     INTEGER NOT NULL, -- eventKind
+		task     BLOB    NOT NULL  -- marshalled todo.v2.Task
+	)`,
+	// optimistic concurrency, incremented by every update
+	`ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1`,
+	// tasks belong to the subject which added them, the ones added
+	// before have no owner.
+	`ALTER TABLE tasks ADD COLUMN owner TEXT NOT NULL DEFAULT ''`,
+	`CREATE INDEX tasks_owner ON tasks (owner, id)`,
+}
+
+type sqliteDB struct {
+	db      *sql.DB
+	changes notifier
+}
+
+// newSQLiteDB opens, or creates, the SQLite database at path and
+// migrates it to the latest schema.
+func newSQLiteDB(path string) (*sqliteDB, error) {
+	// WAL lets readers (ListTasks) run alongside a writer, busy_timeout
+	// makes concurrent writers wait instead of failing, and immediate
+	// transactions take the write lock upfront so they cannot deadlock.
+	q := url.Values{}
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Set("_txlock", "immediate")
+	db, err := sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	d := &sqliteDB{db: db}
+	if err := d.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed migrating %s: %w", path, err)
+	}
+	return d, nil
+}
+
+func (d *sqliteDB) migrate() error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var version int
+	if err := tx.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > len(sqliteMigrations) {
+		return fmt.Errorf("unknown schema version %d", version)
+	}
+	for i, m := range sqliteMigrations[version:] {
+		if _, err := tx.Exec(m); err != nil {
+			return fmt.Errorf("migration %d: %w", version+i+1, err)
+		}
+	}
+	// PRAGMA doesn't support placeholders
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", len(sqliteMigrations))); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (d *sqliteDB) Close() error {
+	return d.db.Close()
+}
+
+func (d *sqliteDB) addTask(ctx context.Context, owner, description string, dueDate time.Time) (uint64, error) {
+	ids, err := d.addTasks(ctx, []newTask{{owner, description, dueDate}})
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+func (d *sqliteDB) addTasks(ctx context.Context, tasks []newTask) ([]uint64, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, sqliteError(err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]uint64, 0, len(tasks))
+	for _, t := range tasks {
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO tasks (description, due_date, owner) VALUES (?, ?, ?)",
+			t.description, t.dueDate.UnixNano(), t.owner,
+		)
+		if err != nil {
+			return nil, sqliteError(err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		task := &pb.Task{
+			Id:          uint64(id),
+			Description: t.description,
+			DueDate:     timestamppb.New(t.dueDate),
+			Version:     1,
+			Owner:       t.owner,
+		}
+		if err := d.record(ctx, tx, eventCreated, task); err != nil {
+			return nil, err
+		}
+		ids = append(ids, uint64(id))
+	}
+	return ids, d.commit(tx)
+}
+
+// record appends a change of task to the change feed, discarding the
+// changes older than eventRetention.
+func (d *sqliteDB) record(ctx context.Context, tx *sql.Tx, kind eventKind, task *pb.Task) error {
+	b, err := proto.Marshal(task)
+	if err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, "INSERT INTO task_events (kind, task) VALUES (?, ?)", kind, b)
+	if err != nil {
+		return sqliteError(err)
+	}
+	rev, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM task_events WHERE revision <= ?", rev-eventRetention)
+	return sqliteError(err)
+}
+
+// commit commits tx and wakes up the watchers.
+func (d *sqliteDB) commit(tx *sql.Tx) error {
+	if err := tx.Commit(); err != nil {
+		return sqliteError(err)
+	}
+	d.changes.notify()
+	return nil
+}
+
+func (d *sqliteDB) getTask(ctx context.Context, id uint64) (*pb.Task, error) {
+	task, err := scanSQLiteTask(d.db.QueryRowContext(ctx,
+		"SELECT id, description, done, due_date, version, owner FROM tasks WHERE id = ?", id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, taskNotFound(id)
+	}
+	if err != nil {
+		return nil, sqliteError(err)
+	}
+	return task, nil
+}
+
+// getTasks calls f for every task selected by q, in q.order.
+//
+// The tasks are read sqliteBatchSize at a time and f is called once the
+// batch is read, so a slow f doesn't keep a read transaction open,
+// which would prevent checkpointing the WAL, for the whole listing.
+// Each batch resumes after the last task of the previous one, so tasks
+// added or updated during the iteration will be visible to f if they
+// sort after it.
+func (d *sqliteDB) getTasks(ctx context.Context, q taskQuery, f func(any) error) error {
+	key, dir := sqliteOrderKey(q.order.field), "ASC"
+	if q.order.desc {
+		dir = "DESC"
+	}
+	remaining := q.limit
+	for {
+		size := sqliteBatchSize
+		if q.limit > 0 && remaining < size {
+			size = remaining
+		}
+		batch, err := d.getBatch(ctx, q, key, dir, size)
+		if err != nil {
+			return err
+		}
+
+		for _, task := range batch {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := f(task); err != nil {
+				return err
+			}
+		}
+		remaining -= len(batch)
+		if len(batch) < size || (q.limit > 0 && remaining == 0) {
+			return nil
+		}
+		q.after = batch[len(batch)-1]
+	}
+}
+
+// getBatch reads the first size tasks selected by q, sorted by key in
+// the direction dir.
+func (d *sqliteDB) getBatch(ctx context.Context, q taskQuery, key, dir string, size int) ([]*pb.Task, error) {
+	where, args := sqliteWhere(q)
+	rows, err := d.db.QueryContext(ctx,
+		"SELECT id, description, done, due_date, version, owner FROM tasks"+where+
+			" ORDER BY "+key+" "+dir+", id "+dir+" LIMIT ?",
+		append(args, size)...,
+	)
+	if err != nil {
+		return nil, sqliteError(err)
+	}
+	defer rows.Close()
+
+	batch := make([]*pb.Task, 0, size)
+	for rows.Next() {
+		task, err := scanSQLiteTask(rows)
+		if err != nil {
+			return nil, sqliteError(err)
+		}
+		batch = append(batch, task)
+	}
+	return batch, sqliteError(rows.Err())
+}
+
+func (d *sqliteDB) updateTask(ctx context.Context, id uint64, update *pb.Task, mask *fieldmaskpb.FieldMask, pre precondition) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sqliteError(err)
+	}
+	defer tx.Rollback()
+
+	task, err := scanSQLiteTask(tx.QueryRowContext(ctx,
+		"SELECT id, description, done, due_date, version, owner FROM tasks WHERE id = ?", id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return taskNotFound(id)
+	}
+	if err != nil {
+		return sqliteError(err)
+	}
+	if err := pre.check(task); err != nil {
+		return err
+	}
+
+	applyUpdate(task, update, mask)
+	var dueDate sql.NullInt64
+	if task.DueDate != nil {
+		dueDate = sql.NullInt64{Int64: task.DueDate.AsTime().UnixNano(), Valid: true}
+	}
+	_, err = tx.ExecContext(ctx,
+		"UPDATE tasks SET description = ?, due_date = ?, done = ?, version = ? WHERE id = ?",
+		task.Description, dueDate, task.Done, task.Version, id,
+	)
+	if err != nil {
+		return sqliteError(err)
+	}
+	if err := d.record(ctx, tx, eventUpdated, task); err != nil {
+		return err
+	}
+	return d.commit(tx)
+}
+
+// deleteTask rolls the deletion back if the task doesn't have the
+// precondition.
+func (d *sqliteDB) deleteTask(ctx context.Context, id uint64, pre precondition) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sqliteError(err)
+	}
+	defer tx.Rollback()
+
+	task, err := scanSQLiteTask(tx.QueryRowContext(ctx,
+		"DELETE FROM tasks WHERE id = ? RETURNING id, description, done, due_date, version, owner", id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return taskNotFound(id)
+	}
+	if err != nil {
+		return sqliteError(err)
+	}
+	if err := pre.check(task); err != nil {
+		return err
+	}
+	if err := d.record(ctx, tx, eventDeleted, task); err != nil {
+		return err
+	}
+	return d.commit(tx)
+}
+
+// watchTasks also polls the database, which can be written by other
+// processes.
+func (d *sqliteDB) watchTasks(ctx context.Context, since uint64, f func(taskEvent) error) error {
+	return watchChanges(ctx, d, &d.changes, watchPollInterval, since, f)
+}
+
+func (d *sqliteDB) lastRevision(ctx context.Context) (uint64, error) {
+	var rev uint64
+	err := d.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(revision), 0) FROM task_events").Scan(&rev)
+	return rev, sqliteError(err)
+}
+
+func (d *sqliteDB) eventsAfter(ctx context.Context, rev uint64) ([]taskEvent, error) {
+	rows, err := d.db.QueryContext(ctx,
+		"SELECT revision, kind, task FROM task_events WHERE revision > ? ORDER BY revision LIMIT ?",
+		rev, eventBatchSize,
+	)
+	if err != nil {
+		return nil, sqliteError(err)
+	}
+	defer rows.Close()
+
+	var events []taskEvent
+	for rows.Next() {
+		var (
+			e    taskEvent
+			task []byte
+		)
+		if err := rows.Scan(&e.revision, &e.kind, &task); err != nil {
+			return nil, sqliteError(err)
+		}
+		e.task = &pb.Task{}
+		if err := proto.Unmarshal(task, e.task); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, sqliteError(rows.Err())
+}
+
+// sqliteNoDueDate is the due_date tasks without due date are sorted by.
+const sqliteNoDueDate = "9223372036854775807" // max int64
+
+// sqliteOrderKey returns the expression tasks are sorted by for field.
+func sqliteOrderKey(field orderField) string {
+	switch field {
+	case orderByDueDate:
+		return "COALESCE(due_date, " + sqliteNoDueDate + ")"
+	case orderByDescription:
+		return "description"
+	case orderByDone:
+		return "done"
+	default:
+		return "id"
+	}
+}
+
+// sqliteWhere returns the WHERE clause, with a leading space, and its
+// arguments selecting the tasks of q. Tasks without due date have a
+// NULL due_date, which never matches the date criteria of the filter.
+func sqliteWhere(q taskQuery) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	cond := func(c string, arg ...any) {
+		conds = append(conds, c)
+		args = append(args, arg...)
+	}
+
+	cond("owner = ?", q.owner)
+	if after := q.after; after != nil {
+		op := ">"
+		if q.order.desc {
+			op = "<"
+		}
+		switch q.order.field {
+		case orderByID:
+			cond("id "+op+" ?", after.Id)
+		case orderByDueDate:
+			var dueDate sql.NullInt64
+			if after.DueDate != nil {
+				dueDate = sql.NullInt64{Int64: after.DueDate.AsTime().UnixNano(), Valid: true}
+			}
+			cond("(COALESCE(due_date, "+sqliteNoDueDate+"), id) "+op+" (COALESCE(?, "+sqliteNoDueDate+"), ?)", dueDate, after.Id)
+		case orderByDescription:
+			cond("(description, id) "+op+" (?, ?)", after.Description, after.Id)
+		case orderByDone:
+			cond("(done, id) "+op+" (?, ?)", after.Done, after.Id)
+		}
+	}
+
+	f := q.filter
+	if f.done != nil {
+		cond("done = ?", *f.done)
+	}
+	if !f.overdueAt.IsZero() {
+		cond("done = 0 AND due_date < ?", f.overdueAt.UnixNano())
+	}
+	if !f.dueAfter.IsZero() {
+		cond("due_date >= ?", f.dueAfter.UnixNano())
+	}
+	if !f.dueBefore.IsZero() {
+		cond("due_date < ?", f.dueBefore.UnixNano())
+	}
+	if f.description != "" {
+		// instr is case-sensitive, unlike LIKE
+		cond("instr(description, ?) > 0", f.description)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// scanSQLiteTask reads the id, description, done, due_date, version and
+// owner columns, in that order, into a task.
+func scanSQLiteTask(row interface{ Scan(...any) error }) (*pb.Task, error) {
+	var (
+		task    pb.Task
+		dueDate sql.NullInt64
+	)
+	if err := row.Scan(&task.Id, &task.Description, &task.Done, &dueDate, &task.Version, &task.Owner); err != nil {
+		return nil, err
+	}
+	if dueDate.Valid {
+		task.DueDate = timestamppb.New(time.Unix(0, dueDate.Int64))
+	}
+	return &task, nil
+}
+
+// sqliteError wraps the errors caused by concurrent writers with
+// errConflict, other errors are returned as is.
+func sqliteError(err error) error {
+	var serr *sqlite.Error
+	if !errors.As(err, &serr) {
+		return err
+	}
+	// extended result codes keep the primary one in the low byte
+	switch serr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return fmt.Errorf("%w: %w", errConflict, err)
+	default:
+		return err
+	}
+}
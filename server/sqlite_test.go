@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func newTestSQLiteDB(t *testing.T, path string) *sqliteDB {
+	t.Helper()
+	d, err := newSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("failed opening sqlite database: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func TestSQLiteDB(t *testing.T) {
+	testDB(t, func(t *testing.T) db {
+		return newTestSQLiteDB(t, filepath.Join(t.TempDir(), "todo.db"))
+	})
+	t.Run("Reopen", testSQLiteReopen)
+	t.Run("Migrate", testSQLiteMigrate)
+	t.Run("WatchOtherProcess", testSQLiteWatchOtherProcess)
+	t.Run("GetTasksCheckpoint", testSQLiteGetTasksCheckpoint)
+}
+
+func testSQLiteReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.db")
+	d := newTestSQLiteDB(t, path)
+	ids := addTasks(t, d, 2)
+	if err := d.deleteTask(context.TODO(), ids[1], precondition{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Close()
+
+	// migrations are only applied once, tasks and the last allocated
+	// id survive a restart.
+	d = newTestSQLiteDB(t, path)
+	tasks := listTasks(t, d)
+	if len(tasks) != 1 || tasks[0].Id != ids[0] {
+		t.Fatalf("expected task %d, got %v", ids[0], tasks)
+	}
+	id, err := d.addTask(context.TODO(), "", "after restart", tasks[0].DueDate.AsTime())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id <= ids[1] {
+		t.Errorf("id %d reused after restart", id)
+	}
+}
+
+func testSQLiteMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.db")
+	old, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// database created by the first version of the schema, the last
+	// task was deleted.
+	for _, stmt := range []string{
+		sqliteMigrations[0],
+		"PRAGMA user_version = 1",
+		"INSERT INTO tasks (description, due_date) VALUES ('task 1', 1), ('task 2', 2), ('task 3', 3)",
+		"DELETE FROM tasks WHERE id = 3",
+	} {
+		if _, err := old.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	old.Close()
+
+	d := newTestSQLiteDB(t, path)
+	tasks := listTasks(t, d)
+	if len(tasks) != 2 || tasks[1].Id != 2 || tasks[1].Description != "task 2" || tasks[1].DueDate.AsTime().UnixNano() != 2 {
+		t.Fatalf("tasks not migrated: %v", tasks)
+	}
+	id, err := d.addTask(context.TODO(), "", "task 4", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != 4 {
+		t.Errorf("expected id 4 after migration, got %d", id)
+	}
+	// due dates can be removed
+	if err := d.updateTask(context.TODO(), id, &pb.Task{}, &fieldmaskpb.FieldMask{Paths: []string{"due_date"}}, precondition{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func testSQLiteWatchOtherProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.db")
+	watched := newTestSQLiteDB(t, path)
+	other := newTestSQLiteDB(t, path)
+
+	// since 0 would only watch the changes after the watch starts
+	addTasks(t, watched, 1)
+	since, err := watched.lastRevision(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events := watchEvents(t, watched, since)
+	// not notified, only seen by polling
+	ids := addTasks(t, other, 1)
+	if e := nextEvent(t, events); e.kind != eventCreated || e.task.Id != ids[0] {
+		t.Errorf("expected task %d created, got %v", ids[0], e)
+	}
+}
+
+// testSQLiteGetTasksCheckpoint checks a slow listing doesn't keep a read
+// transaction open, which would prevent checkpointing the writes made
+// meanwhile.
+func testSQLiteGetTasksCheckpoint(t *testing.T) {
+	d := newTestSQLiteDB(t, filepath.Join(t.TempDir(), "todo.db"))
+	ids := addTasks(t, d, 2*sqliteBatchSize+1)
+
+	var seen int
+	err := d.getTasks(context.TODO(), taskQuery{}, func(a any) error {
+		seen++
+		if a.(*pb.Task).Id != ids[0] {
+			return nil
+		}
+		if _, err := d.addTask(context.TODO(), "", "added while listing", time.Now()); err != nil {
+			return err
+		}
+		var busy, frames, checkpointed int
+		if err := d.db.QueryRow("PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &frames, &checkpointed); err != nil {
+			return err
+		}
+		if busy != 0 {
+			t.Errorf("expected the checkpoint not to be blocked by the listing")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the task added sorts after the current one
+	if seen != len(ids)+1 {
+		t.Errorf("expected %d tasks, got %d", len(ids)+1, seen)
+	}
+}
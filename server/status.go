@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// unavailableRetryDelay is the delay suggested to clients when the
+// database is unavailable.
+const unavailableRetryDelay = time.Second
+
+// taskResourceType is the resource type of tasks in error details.
+var taskResourceType = string((&pb.Task{}).ProtoReflect().Descriptor().FullName())
+
+// toStatus translates an error returned by the db, or any other error
+// happening while handling a request, into a gRPC status error.
+// Errors that already are status errors are returned as is.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	var (
+		code    codes.Code
+		details []protoiface.MessageV1
+	)
+	switch {
+	case errors.Is(err, errNotFound):
+		code = codes.NotFound
+	case errors.Is(err, errConflict), errors.Is(err, errVersionMismatch):
+		code = codes.Aborted
+	case errors.Is(err, errPermissionDenied):
+		code = codes.PermissionDenied
+	case errors.Is(err, errCompacted):
+		code = codes.OutOfRange
+	case errors.Is(err, errUnavailable):
+		code = codes.Unavailable
+		details = append(details, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(unavailableRetryDelay),
+		})
+	default:
+		return status.Errorf(codes.Internal, "unexpected error: %s", err.Error())
+	}
+
+	var te *taskError
+	if errors.As(err, &te) {
+		details = append(details, &errdetails.ResourceInfo{
+			ResourceType: taskResourceType,
+			ResourceName: strconv.FormatUint(te.id, 10),
+			Description:  te.Error(),
+		})
+	}
+
+	s := status.New(code, err.Error())
+	if len(details) == 0 {
+		return s.Err()
+	}
+	ds, err := s.WithDetails(details...)
+	if err != nil {
+		return s.Err()
+	}
+	return ds.Err()
+}
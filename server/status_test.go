@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		code     codes.Code
+		resource string
+		retry    bool
+	}{
+		{"NotFound", taskNotFound(3), codes.NotFound, "3", false},
+		{"WrappedNotFound", fmt.Errorf("updating: %w", taskNotFound(4)), codes.NotFound, "4", false},
+		{"Conflict", &taskError{id: 5, err: errConflict}, codes.Aborted, "5", false},
+		{"Unavailable", errUnavailable, codes.Unavailable, "", true},
+		{"VersionMismatch", &taskError{id: 6, err: errVersionMismatch}, codes.Aborted, "6", false},
+		{"PermissionDenied", &taskError{id: 7, err: errPermissionDenied}, codes.PermissionDenied, "7", false},
+		{"Compacted", errCompacted, codes.OutOfRange, "", false},
+		{"Canceled", context.Canceled, codes.Canceled, "", false},
+		{"DeadlineExceeded", fmt.Errorf("query: %w", context.DeadlineExceeded), codes.DeadlineExceeded, "", false},
+		{"Status", status.Error(codes.InvalidArgument, "invalid"), codes.InvalidArgument, "", false},
+		{"Unexpected", errors.New("boom"), codes.Internal, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := status.Convert(toStatus(tt.err))
+			if s.Code() != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, s.Code())
+			}
+
+			var (
+				info  *errdetails.ResourceInfo
+				retry *errdetails.RetryInfo
+			)
+			for _, d := range s.Details() {
+				switch d := d.(type) {
+				case *errdetails.ResourceInfo:
+					info = d
+				case *errdetails.RetryInfo:
+					retry = d
+				}
+			}
+			if tt.resource != "" {
+				if info == nil || info.ResourceName != tt.resource || info.ResourceType != "todo.v2.Task" {
+					t.Errorf("expected ResourceInfo for task %s, got %v", tt.resource, info)
+				}
+			} else if info != nil {
+				t.Errorf("unexpected ResourceInfo: %v", info)
+			}
+			if tt.retry != (retry != nil) {
+				t.Errorf("expected RetryInfo: %t, got %v", tt.retry, retry)
+			}
+		})
+	}
+
+	if toStatus(nil) != nil {
+		t.Errorf("expected nil status for nil error")
+	}
+}
@@ -1,7 +1,10 @@
 package main
 
+import "time"
+
 type testOptions struct {
 	isAvailable bool
+	delay       time.Duration
 }
 
 var defaultTestOptions = testOptions{
@@ -31,3 +34,10 @@ func IsAvailable(a bool) TestOption {
 		o.isAvailable = a
 	})
 }
+
+// Delay makes every call to the database take d.
+func Delay(d time.Duration) TestOption {
+	return newFuncTestOption(func(o *testOptions) {
+		o.delay = d
+	})
+}
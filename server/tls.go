@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// newServerCredentials returns the TLS credentials of the server, with
+// the certificate and key in the PEM files certFile and keyFile. If
+// clientCAFile isn't empty, the clients are required to present a
+// certificate signed by one of the CAs of this PEM file (mTLS).
+func newServerCredentials(certFile, keyFile, clientCAFile string) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCAFile != "" {
+		b, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("%s: no PEM certificate", clientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// clientIdentity returns the identity (see certIdentity) of the client
+// certificate of the call of ctx, if it was verified with mTLS.
+func clientIdentity(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	id := certIdentity(info.State.VerifiedChains[0][0])
+	return id, id != ""
+}
+
+// certIdentity returns the identity of a client certificate: its SPIFFE
+// ID if it has one, otherwise its first DNS name, email address or URI
+// in this order, otherwise its common name.
+func certIdentity(cert *x509.Certificate) string {
+	for _, u := range cert.URIs {
+		if u.Scheme == "spiffe" {
+			return u.String()
+		}
+	}
+	switch {
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	case len(cert.EmailAddresses) > 0:
+		return cert.EmailAddresses[0]
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	}
+	return cert.Subject.CommonName
+}
+
+// errNoClientCert is returned by certAuthenticator for the calls without
+// a verified client certificate.
+var errNoClientCert = fmt.Errorf("%w: no verified client certificate", errInvalidToken)
+
+// certAuthenticator authenticates the clients by their certificate,
+// verified with mTLS, rather than by a token.
+type certAuthenticator struct{}
+
+func newCertAuthenticator(string) (Authenticator, error) {
+	if *clientCA == "" {
+		return nil, errors.New("requires -client-ca")
+	}
+	return certAuthenticator{}, nil
+}
+
+func (certAuthenticator) Authenticate(ctx context.Context, _ string) (*Principal, error) {
+	id, ok := clientIdentity(ctx)
+	if !ok {
+		return nil, errNoClientCert
+	}
+	return &Principal{Subject: id, Peer: id}, nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	grpcauth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testCA issues the certificates of the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns a certificate signed by ca from tmpl, which only needs
+// the identity fields.
+func (ca *testCA) issue(t *testing.T, tmpl *x509.Certificate, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCertIdentity(t *testing.T) {
+	spiffe, _ := url.Parse("spiffe://example.com/ns/todo/sa/client")
+	other, _ := url.Parse("https://client.example.com")
+	tests := []struct {
+		name     string
+		cert     *x509.Certificate
+		expected string
+	}{
+		{"SPIFFE", &x509.Certificate{
+			Subject:  pkix.Name{CommonName: "cn"},
+			DNSNames: []string{"client.example.com"},
+			URIs:     []*url.URL{other, spiffe},
+		}, spiffe.String()},
+		{"DNS", &x509.Certificate{
+			Subject:        pkix.Name{CommonName: "cn"},
+			DNSNames:       []string{"client.example.com"},
+			EmailAddresses: []string{"client@example.com"},
+		}, "client.example.com"},
+		{"Email", &x509.Certificate{
+			Subject:        pkix.Name{CommonName: "cn"},
+			EmailAddresses: []string{"client@example.com"},
+		}, "client@example.com"},
+		{"URI", &x509.Certificate{
+			Subject: pkix.Name{CommonName: "cn"},
+			URIs:    []*url.URL{other},
+		}, other.String()},
+		{"CommonName", &x509.Certificate{Subject: pkix.Name{CommonName: "cn"}}, "cn"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := certIdentity(tt.cert); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// peerContext returns a context of a call with the client certificate
+// cert verified.
+func peerContext(cert *x509.Certificate) context.Context {
+	return peer.NewContext(context.TODO(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestValidateAuthTokenPeer(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "client"}}
+
+	ctx, err := validateAuthToken(peerContext(cert), certAuthenticator{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p, _ := principalFromContext(ctx); p == nil || p.Subject != "client" || p.Peer != "client" {
+		t.Errorf("expected client principal, got %v", p)
+	}
+	_, err = validateAuthToken(context.TODO(), certAuthenticator{})
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Errorf("expected %v without certificate, got %v", codes.Unauthenticated, err)
+	}
+
+	// tokens still authenticate the subject, the certificate is kept
+	a := AuthenticatorFunc(func(context.Context, string) (*Principal, error) {
+		return &Principal{Subject: "alice"}, nil
+	})
+	ctx = metadata.NewIncomingContext(peerContext(cert), metadata.Pairs(authTokenKey, "alice"))
+	if ctx, err = validateAuthToken(ctx, a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p, _ := principalFromContext(ctx); p == nil || p.Subject != "alice" || p.Peer != "client" {
+		t.Errorf("expected alice principal with client peer, got %v", p)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	serverCert := ca.issue(t, &x509.Certificate{DNSNames: []string{"x.test.example.com"}}, x509.ExtKeyUsageServerAuth)
+	serverKey, err := x509.MarshalPKCS8PrivateKey(serverCert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, "server_cert.pem")
+	keyFile := filepath.Join(dir, "server_key.pem")
+	caFile := filepath.Join(dir, "ca_cert.pem")
+	writePEM(t, certFile, "CERTIFICATE", serverCert.Certificate[0])
+	writePEM(t, keyFile, "PRIVATE KEY", serverKey)
+	writePEM(t, caFile, "CERTIFICATE", ca.cert.Raw)
+
+	creds, err := newServerCredentials(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	principals := make(chan *Principal, 1)
+	record := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		p, _ := principalFromContext(ctx)
+		principals <- p
+		return handler(ctx, req)
+	}
+	s := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.ChainUnaryInterceptor(grpcauth.UnaryServerInterceptor(authFunc(certAuthenticator{})), record),
+	)
+	pb.RegisterTodoServiceServer(s, newServer(New()))
+	lis := bufconn.Listen(bufSize)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	call := func(t *testing.T, certs ...tls.Certificate) error {
+		t.Helper()
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		creds := credentials.NewTLS(&tls.Config{
+			RootCAs:      roots,
+			Certificates: certs,
+			ServerName:   "x.test.example.com",
+		})
+		dialer := func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}
+		conn, err := grpc.DialContext(context.Background(), "bufnet", grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(creds))
+		if err != nil {
+			t.Fatalf("failed to dial bufnet: %v", err)
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = pb.NewTodoServiceClient(conn).GetTask(ctx, &pb.GetTaskRequest{Id: 1})
+		return err
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		spiffe, _ := url.Parse("spiffe://example.com/ns/todo/sa/client")
+		cert := ca.issue(t, &x509.Certificate{
+			Subject: pkix.Name{CommonName: "client"},
+			URIs:    []*url.URL{spiffe},
+		}, x509.ExtKeyUsageClientAuth)
+		// authenticated, the task doesn't exist
+		if err := call(t, cert); status.Code(err) != codes.NotFound {
+			t.Fatalf("expected %v, got %v", codes.NotFound, err)
+		}
+		if p := <-principals; p.Subject != spiffe.String() {
+			t.Errorf("expected %q, got %v", spiffe, p)
+		}
+	})
+
+	t.Run("NoCertificate", func(t *testing.T) {
+		if err := call(t); status.Code(err) != codes.Unavailable {
+			t.Errorf("expected %v, got %v", codes.Unavailable, err)
+		}
+	})
+
+	t.Run("OtherCA", func(t *testing.T) {
+		cert := newTestCA(t).issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "client"}}, x509.ExtKeyUsageClientAuth)
+		if err := call(t, cert); status.Code(err) != codes.Unavailable {
+			t.Errorf("expected %v, got %v", codes.Unavailable, err)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/snirkop89/grpc-go-pro/proto/todo/v2"
+)
+
+const (
+	// eventRetention is the number of changes kept by the db to resume
+	// watches, older ones are discarded.
+	eventRetention = 10000
+	// eventBatchSize is the maximum number of changes read at once by
+	// watchTasks.
+	eventBatchSize = 64
+	// watchPollInterval is the interval between two reads of the
+	// changes written by other processes, for the backends allowing it.
+	watchPollInterval = 500 * time.Millisecond
+)
+
+// eventKind is the kind of change of a taskEvent.
+type eventKind int
+
+const (
+	eventCreated eventKind = iota + 1
+	eventUpdated
+	eventDeleted
+)
+
+// taskEvent is a change of a task.
+type taskEvent struct {
+	revision uint64
+	kind     eventKind
+	// task is the task after the change, or before it was deleted.
+	task *pb.Task
+}
+
+// toResponse converts e for WatchTasks.
+func (e taskEvent) toResponse() *pb.WatchTasksResponse {
+	res := &pb.WatchTasksResponse{
+		Task:     e.task,
+		Revision: e.revision,
+	}
+	switch e.kind {
+	case eventCreated:
+		res.Type = pb.WatchTasksResponse_TYPE_CREATED
+	case eventUpdated:
+		res.Type = pb.WatchTasksResponse_TYPE_UPDATED
+	case eventDeleted:
+		res.Type = pb.WatchTasksResponse_TYPE_DELETED
+	}
+	return res
+}
+
+// changeLog is the change feed of a db, backing its watchTasks method
+// (see watchChanges).
+type changeLog interface {
+	// lastRevision returns the revision of the last change, 0 if there
+	// was none.
+	lastRevision(ctx context.Context) (uint64, error)
+	// eventsAfter returns, in order, up to eventBatchSize changes with a
+	// revision greater than rev. Revisions should be contiguous, a gap
+	// after rev means some changes were discarded.
+	eventsAfter(ctx context.Context, rev uint64) ([]taskEvent, error)
+}
+
+// notifier wakes up the watchers of a db when a change is written.
+// The zero value is ready to use.
+type notifier struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// wait returns a channel closed by the next call to notify.
+func (n *notifier) wait() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.ch == nil {
+		n.ch = make(chan struct{})
+	}
+	return n.ch
+}
+
+func (n *notifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.ch != nil {
+		close(n.ch)
+		n.ch = nil
+	}
+}
+
+// watchChanges implements watchTasks on top of a changeLog: it calls f
+// with the changes of c after since, or after the last one if since is
+// 0, in order, until ctx is done or f returns an error. A since after
+// the last change, which the revisions of a memory-backed server
+// starting over would give, is reported as errCompacted rather than
+// skipping the changes up to it.
+//
+// New changes are read when n is notified or, if poll isn't 0, every
+// poll. Polling is needed when other processes write to the database.
+func watchChanges(ctx context.Context, c changeLog, n *notifier, poll time.Duration, since uint64, f func(taskEvent) error) error {
+	rev, err := c.lastRevision(ctx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case since == 0:
+		since = rev
+	case since > rev:
+		return errCompacted
+	}
+
+	var tick <-chan time.Time
+	if poll > 0 {
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		// taken before reading so that no notification is missed
+		changed := n.wait()
+		events, err := c.eventsAfter(ctx, since)
+		if err != nil {
+			return err
+		}
+		if len(events) > 0 && events[0].revision > since+1 {
+			return errCompacted
+		}
+		for _, e := range events {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := f(e); err != nil {
+				return err
+			}
+			since = e.revision
+		}
+		if len(events) == eventBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		case <-tick:
+		}
+	}
+}